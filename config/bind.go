@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// boundValue is a parsed value and the config revision it was parsed from.
+type boundValue[T any] struct {
+	revision uint64
+	value    T
+}
+
+// Bind registers key (or the whole configuration if key is "") for cached reads and
+// returns a getter for its current value unmarshalled into T. The value is parsed
+// once up front and again only after the configuration changes (Set, MergeConfigMap,
+// Unset, or a reload from WatchConfig or WatchValidated); in between the getter is a
+// lock-free atomic load, so it is cheap enough for per-request use.
+//
+// Panics if the initial unmarshal fails, since that means the configuration doesn't
+// match T. If a later change can't be unmarshalled, the getter keeps returning the
+// last good value and the error is passed to Options.OnReloadError. The same value is
+// shared by all callers, so treat maps and slices in it as read-only. Changes made
+// directly on Viper() are not noticed.
+//
+// For a two-field struct (BenchmarkBind), UnmarshalKey costs about 5µs and 26
+// allocations (944 B) per call; the Bind getter costs about 4ns and no allocations.
+//
+// Example:
+//
+//	type LimitsConfig struct {
+//	    MaxUploadMB int           `mapstructure:"max_upload_mb"`
+//	    Timeout     time.Duration `mapstructure:"timeout"`
+//	}
+//
+//	limits := config.Bind[LimitsConfig](cfg, "limits")
+//	cfg.WatchConfig()
+//
+//	app.Post("/upload", func(c *fiber.Ctx) error {
+//	    if len(c.Body()) > limits().MaxUploadMB<<20 {
+//	        return fiber.ErrRequestEntityTooLarge
+//	    }
+//	    // ...
+//	})
+func Bind[T any](cfg *Config, key string) func() T {
+	initial, err := parseBound[T](cfg, key)
+	if err != nil {
+		panic(fmt.Sprintf("config: bind %q: %v", key, err))
+	}
+
+	var (
+		current atomic.Pointer[boundValue[T]]
+		mu      sync.Mutex // Serialises re-parsing so a change is parsed once
+	)
+	current.Store(initial)
+
+	return func() T {
+		b := current.Load()
+		if b.revision == cfg.revision.Load() {
+			return b.value
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if b = current.Load(); b.revision == cfg.revision.Load() {
+			return b.value
+		}
+
+		next, err := parseBound[T](cfg, key)
+		if err != nil {
+			cfg.reportReloadError(fmt.Errorf("config: bind %q: %w", key, err))
+			// Keep the last good value without retrying until the next change
+			next = &boundValue[T]{revision: next.revision, value: b.value}
+		}
+		current.Store(next)
+		return next.value
+	}
+}
+
+// parseBound unmarshals key (or everything if key is "") into a new T, recording the
+// revision it was read at. The revision is set even when unmarshalling fails.
+func parseBound[T any](cfg *Config, key string) (*boundValue[T], error) {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+
+	b := &boundValue[T]{revision: cfg.revision.Load()}
+	if key == "" {
+		return b, cfg.viper.Unmarshal(&b.value)
+	}
+	return b, cfg.viper.UnmarshalKey(key, &b.value)
+}
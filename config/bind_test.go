@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindLimits struct {
+	MaxUploadMB int           `mapstructure:"max_upload_mb"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+}
+
+func TestBindReparsesOnChange(t *testing.T) {
+	cfg, err := New(nil)
+	require.NoError(t, err)
+	cfg.Set("limits.max_upload_mb", 10)
+	cfg.Set("limits.timeout", "5s")
+
+	limits := Bind[bindLimits](cfg, "limits")
+	assert.Equal(t, bindLimits{MaxUploadMB: 10, Timeout: 5 * time.Second}, limits())
+
+	cfg.Set("limits.max_upload_mb", 20)
+	assert.Equal(t, 20, limits().MaxUploadMB)
+
+	require.NoError(t, cfg.Unset("limits.max_upload_mb"))
+	assert.Equal(t, bindLimits{Timeout: 5 * time.Second}, limits())
+}
+
+func TestBindWholeConfigFollowsMerges(t *testing.T) {
+	cfg, err := New(nil)
+	require.NoError(t, err)
+	require.NoError(t, cfg.MergeConfigMap(map[string]interface{}{"limits": map[string]interface{}{"timeout": "5s"}}))
+
+	all := Bind[struct {
+		Limits bindLimits `mapstructure:"limits"`
+	}](cfg, "")
+	assert.Equal(t, 5*time.Second, all().Limits.Timeout)
+
+	require.NoError(t, cfg.MergeConfigMap(map[string]interface{}{"limits": map[string]interface{}{"timeout": "1m"}}))
+	assert.Equal(t, time.Minute, all().Limits.Timeout)
+}
+
+func TestBindKeepsLastGoodValue(t *testing.T) {
+	var reported error
+	cfg, err := New(&Options{OnReloadError: func(err error) { reported = err }})
+	require.NoError(t, err)
+	cfg.Set("limits.max_upload_mb", 10)
+
+	limits := Bind[bindLimits](cfg, "limits")
+	cfg.Set("limits.max_upload_mb", "lots")
+	assert.Equal(t, 10, limits().MaxUploadMB)
+	assert.ErrorContains(t, reported, `bind "limits"`)
+
+	assert.Panics(t, func() { Bind[bindLimits](cfg, "limits") })
+}
+
+func TestBindFollowsWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "limits:\n  max_upload_mb: 10\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+	limits := Bind[bindLimits](cfg, "limits")
+
+	changed := make(chan struct{}, 1)
+	cfg.Watch(func() { changed <- struct{}{} })
+	cfg.WatchConfig()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("limits:\n  max_upload_mb: 50\n"), 0o644))
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change not observed")
+	}
+	assert.Equal(t, 50, limits().MaxUploadMB)
+}
+
+func BenchmarkBind(b *testing.B) {
+	cfg, err := New(nil)
+	require.NoError(b, err)
+	cfg.Set("limits.max_upload_mb", 10)
+	cfg.Set("limits.timeout", "5s")
+
+	b.Run("UnmarshalKey", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var limits bindLimits
+			_ = cfg.UnmarshalKey("limits", &limits)
+		}
+	})
+
+	b.Run("Bind", func(b *testing.B) {
+		limits := Bind[bindLimits](cfg, "limits")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = limits()
+		}
+	})
+}
@@ -1,21 +1,66 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 )
 
 // Config wraps Viper for type-safe configuration management.
 // It provides a clean, reusable interface for loading and accessing configuration.
 type Config struct {
-	viper *viper.Viper
-	mu    sync.RWMutex
+	viper     *viper.Viper
+	mu        sync.RWMutex
+	envPrefix string            // Prefix applied to environment variable lookups
+	sliceSep  string            // Delimiter for slice values read from environment variables
+	keyCase   map[string]string // Lowercased key path -> original key spelling
+	origins   map[string]string // Lowercased leaf key path -> source tag (see Origin)
+	opts      Options           // Options after defaults, used to rebuild viper
+	source    *dataSource       // In-memory base config (NewFromBytes), nil when read from disk
+
+	// Runtime layers replayed when viper is rebuilt (see Unset)
+	merged    []map[string]interface{} // Maps applied via MergeConfigMap, in order
+	overrides []override               // Values applied via Set, in order
+
+	// Leading entries of merged and overrides produced by Options.Loaders; a reload
+	// runs the loaders again instead of replaying them
+	loaderMerged    int
+	loaderOverrides int
+
+	envBindings map[string][]string // Lowercased key -> variables bound with BindEnv, replayed on rebuild
+	files       *fileLayer          // File layer from the last accepted load, restored by Unset
+	envSlices   map[string]string   // Lowercased key -> origin of a list built from indexed variables (see EnvSliceKeys)
+
+	// Callbacks run after WatchConfig applies a change
+	watchers []func()
+	watching bool
+
+	revision atomic.Uint64 // Incremented on every change, under mu; detects races with reloads and stale Bind values
+}
+
+// fileLayer is a snapshot of the values loaded from config files and secret files
+// (before environment variables and runtime layers), so Unset can rebuild viper
+// without reading the filesystem again. It is never modified once taken.
+type fileLayer struct {
+	settings   map[string]interface{}
+	origins    map[string]string
+	configFile string // Base config file, kept for WatchConfig
+}
+
+// override is a runtime value applied with Set.
+type override struct {
+	key   string
+	value interface{}
 }
 
 // Loader is a function that loads configuration from an external source.
@@ -26,24 +71,74 @@ type Loader func(cfg *Config) error
 type Options struct {
 	// ConfigPath is the directory containing config files (default: ".")
 	ConfigPath string
+	// ConfigPaths are directories searched for config files, in increasing precedence
+	// (default: nil = ConfigPath only). Each config name (base, env-specific, and
+	// ConfigNames) is merged from every directory that has it, so later paths override
+	// earlier ones for the same name, e.g. {"/etc/app", "/etc/app-overrides"}.
+	// ConfigPath is ignored when ConfigPaths is set.
+	ConfigPaths []string
 	// ConfigName is the config file name without extension (default: "config")
 	ConfigName string
-	// ConfigType is the file type (yaml, json, toml, etc.) (default: "yaml")
+	// ConfigType forces the base file type (yaml, json, toml, etc.) (default: "" = detect by extension)
 	ConfigType string
-	// Env specifies the environment name for loading env-specific configs (default: "")
-	// If set, loads config.{Env}.yaml after config.yaml
-	Env string
+	// Env specifies the environment for loading env-specific configs (default: "")
+	// If set, loads config.{Env}.{ext} after the base config, with any supported extension
+	// Use the Development, Staging, and Production constants or ParseEnvironment to catch typos.
+	Env Environment
+	// ConfigNames are additional config file names (without extension) merged in order
+	// after the base and env-specific configs (default: nil)
+	// Each file's type is detected from its extension, so formats may differ,
+	// e.g. config.yaml + config.local.json
+	ConfigNames []string
 	// EnvPrefix specifies the prefix for environment variables (default: "")
 	// All environment variables will be auto-bound with this prefix
 	EnvPrefix string
 	// AutoEnvEnabled enables automatic binding of all environment variables (default: true)
-	AutoEnvEnabled bool
-	// LookupsEnv enables case-insensitive environment variable lookup (default: true)
-	LookupsEnv bool
+	// Use Bool(false) to read configuration from files, loaders, and Set only.
+	AutoEnvEnabled *bool
+	// LookupsEnv maps nested keys to environment variable names by replacing "." with "_",
+	// e.g. database.host -> APP_DATABASE_HOST (default: true)
+	// With Bool(false), only top-level keys can come from the environment.
+	LookupsEnv *bool
+	// EnvOnlyKeys are keys (e.g. secrets, infra endpoints) that may only come from the
+	// environment, never from config files (default: nil). File values for these keys
+	// are dropped after loading; secret files, loaders, and runtime Set still apply.
+	EnvOnlyKeys []string
+	// EnvOnlyStrict makes New fail if a config file sets an EnvOnlyKeys key, instead of
+	// silently dropping the value (default: false)
+	EnvOnlyStrict bool
+	// SecretFiles maps config keys to files whose contents become the value (default: nil)
+	// e.g. {"database.password": "/run/secrets/db_password"}. Trailing newlines are trimmed.
+	// {ENV_KEY}_FILE environment variables work the same way, e.g. APP_DATABASE_PASSWORD_FILE
+	SecretFiles map[string]string
+	// OnReloadError is called when WatchValidated rejects a changed config file,
+	// either because it failed to load or failed validation (default: nil = ignored)
+	// The previous configuration stays in effect.
+	OnReloadError func(err error)
+	// EnvSliceKeys are list keys that can also be given as indexed environment variables
+	// (default: nil), e.g. "servers" reads APP_SERVERS_0_HOST, APP_SERVERS_0_PORT,
+	// APP_SERVERS_1_HOST, ... as a list of maps that unmarshals into []ServerConfig.
+	// Fields are lowercased with underscores kept (APP_SERVERS_0_MAX_CONNS -> max_conns),
+	// "__" separates nested fields, and APP_HOSTS_0, APP_HOSTS_1 give scalar items.
+	// Indexes must start at 0 without gaps. When any variable for a key is set, the
+	// list replaces the one from config files and loaders.
+	EnvSliceKeys []string
+	// SliceDelimiter splits slice values provided through a single environment variable (default: ",")
+	// e.g. APP_CORS_ORIGINS=a.com,b.com -> []string{"a.com", "b.com"}
+	SliceDelimiter string
 	// Loaders are custom configuration loaders to execute after initial load (default: nil)
 	Loaders []Loader
 }
 
+// Bool returns a pointer to v, for the optional bool fields of Options.
+//
+// Example:
+//
+//	cfg, err := config.New(&config.Options{AutoEnvEnabled: config.Bool(false)})
+func Bool(v bool) *bool {
+	return &v
+}
+
 var (
 	// Global config instance
 	globalConfig *Config
@@ -54,22 +149,44 @@ var (
 // Default options:
 //   - ConfigPath: "."
 //   - ConfigName: "config"
-//   - ConfigType: "yaml"
+//   - ConfigType: "" (detect by extension)
 //   - EnvPrefix: ""
+//   - SliceDelimiter: ","
 //   - AutoEnvEnabled: true
 //   - LookupsEnv: true
 //
+// Precedence (lowest to highest):
+//  1. Base config ({ConfigName}.{ext})
+//  2. Environment-specific config ({ConfigName}.{Env}.{ext})
+//  3. ConfigNames, in order
+//  4. Secret files ({ENV_KEY}_FILE variables, then SecretFiles)
+//  5. Loaders, in order
+//  6. Environment variables (including EnvSliceKeys lists) and values set at runtime via Set
+//
+// With ConfigPaths, each of layers 1-3 is merged from every directory in order, so
+// an overrides directory's config.yaml wins over the base directory's config.yaml,
+// but not over config.{Env}.{ext} from either directory.
+//
+// When several files share a name with different extensions, the first match in
+// viper.SupportedExts order (json, toml, yaml, yml, ...) is used.
+//
 // Example:
 //
 //	cfg, err := config.New(&config.Options{
 //	    ConfigPath: "./config",
-//	    Env: "production",
+//	    Env: config.Production,
 //	    EnvPrefix: "APP",
 //	})
 //	if err != nil {
 //	    panic(err)
 //	}
 func New(opts *Options) (*Config, error) {
+	return newConfig(opts, nil)
+}
+
+// newConfig applies option defaults, loads the base config (from src if non-nil,
+// otherwise from disk) and the remaining layers, and runs the loaders.
+func newConfig(opts *Options, src *dataSource) (*Config, error) {
 	if opts == nil {
 		opts = &Options{}
 	}
@@ -81,52 +198,51 @@ func New(opts *Options) (*Config, error) {
 	if opts.ConfigName == "" {
 		opts.ConfigName = "config"
 	}
-	if opts.ConfigType == "" {
-		opts.ConfigType = "yaml"
+	if opts.SliceDelimiter == "" {
+		opts.SliceDelimiter = ","
 	}
-	opts.AutoEnvEnabled = true // enabled by default
-	opts.LookupsEnv = true     // enabled by default
-
-	v := viper.New()
-
-	// Configure paths
-	v.AddConfigPath(opts.ConfigPath)
-	v.SetConfigName(opts.ConfigName)
-	v.SetConfigType(opts.ConfigType)
-
-	// Configure environment variables
-	if opts.EnvPrefix != "" {
-		v.SetEnvPrefix(opts.EnvPrefix)
+	if opts.AutoEnvEnabled == nil {
+		opts.AutoEnvEnabled = Bool(true)
 	}
-	if opts.AutoEnvEnabled {
-		v.AutomaticEnv()
-	}
-	if opts.LookupsEnv {
-		v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	if opts.LookupsEnv == nil {
+		opts.LookupsEnv = Bool(true)
 	}
 
-	cfg := &Config{viper: v}
+	cfg := &Config{
+		viper:     newViper(opts),
+		envPrefix: opts.EnvPrefix,
+		sliceSep:  opts.SliceDelimiter,
+		opts:      *opts,
+		source:    src,
+	}
 
-	// Load base config
-	if err := cfg.loadConfig(); err != nil {
+	// Load base, environment-specific, and additional config files
+	if err := cfg.loadFiles(); err != nil {
 		return nil, err
 	}
 
-	// Load environment-specific config if specified
-	if opts.Env != "" {
-		if err := cfg.loadEnvConfig(opts.Env); err != nil {
-			return nil, err
-		}
+	// Execute custom loaders
+	if err := cfg.runLoaders(); err != nil {
+		return nil, err
 	}
 
-	// Execute custom loaders
-	for _, loader := range opts.Loaders {
-		if err := loader(cfg); err != nil {
-			return nil, fmt.Errorf("config loader failed: %w", err)
+	return cfg, nil
+}
+
+// runLoaders runs Options.Loaders in order and marks the Set and MergeConfigMap
+// layers they applied as loader layers. Must run before any other runtime change.
+func (c *Config) runLoaders() error {
+	for _, loader := range c.opts.Loaders {
+		if err := loader(c); err != nil {
+			return fmt.Errorf("config loader failed: %w", err)
 		}
 	}
 
-	return cfg, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loaderMerged = len(c.merged)
+	c.loaderOverrides = len(c.overrides)
+	return nil
 }
 
 // Global returns the global Config instance. Panics if not initialized.
@@ -147,7 +263,7 @@ func Global() *Config {
 //
 // Example:
 //
-//	cfg, err := config.New(&config.Options{Env: "production"})
+//	cfg, err := config.New(&config.Options{Env: config.Production})
 //	if err != nil {
 //	    panic(err)
 //	}
@@ -158,11 +274,252 @@ func SetGlobal(cfg *Config) {
 	})
 }
 
-// loadConfig loads the base configuration file.
+// newViper creates a viper instance configured from opts (paths, type). Environment
+// binding is configured separately by loadEnv, once the file layer is loaded.
+func newViper(opts *Options) *viper.Viper {
+	v := viper.New()
+
+	// Configure paths; the base config is read from the first directory that has it
+	for _, dir := range opts.searchPaths() {
+		v.AddConfigPath(dir)
+	}
+	v.SetConfigName(opts.ConfigName)
+	if opts.ConfigType != "" {
+		v.SetConfigType(opts.ConfigType)
+	}
+
+	return v
+}
+
+// loadEnv configures environment variable binding on viper: EnvPrefix, AutomaticEnv,
+// the key replacer, BindEnv bindings, and EnvSliceKeys lists.
+func (c *Config) loadEnv() error {
+	c.mu.Lock()
+	if c.opts.EnvPrefix != "" {
+		c.viper.SetEnvPrefix(c.opts.EnvPrefix)
+	}
+	if *c.opts.AutoEnvEnabled {
+		c.viper.AutomaticEnv()
+	}
+	if *c.opts.LookupsEnv {
+		c.viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	}
+	for key, envVars := range c.envBindings {
+		if err := c.viper.BindEnv(append([]string{key}, envVars...)...); err != nil {
+			c.mu.Unlock()
+			return err
+		}
+	}
+	c.mu.Unlock()
+
+	return c.applyEnvSlices()
+}
+
+// searchPaths returns the config directories in increasing precedence.
+func (o *Options) searchPaths() []string {
+	if len(o.ConfigPaths) > 0 {
+		return o.ConfigPaths
+	}
+	return []string{o.ConfigPath}
+}
+
+// loadFiles loads the base config, the environment-specific config, any
+// additional ConfigNames, and secret files, in precedence order, snapshots that
+// file layer, then binds environment variables (see loadEnv).
+func (c *Config) loadFiles() error {
+	// Load base config
+	if err := c.loadConfig(); err != nil {
+		return err
+	}
+
+	// Merge the base config from search paths after the one it was read from
+	for _, dir := range c.pathsAfterBase() {
+		if err := c.mergeConfigFile(dir, c.opts.ConfigName); err != nil {
+			return err
+		}
+	}
+
+	// Load environment-specific config if specified
+	if c.opts.Env != "" {
+		if err := c.loadEnvConfig(string(c.opts.Env)); err != nil {
+			return err
+		}
+	}
+
+	// Merge additional named configs in order
+	for _, name := range c.opts.ConfigNames {
+		if err := c.mergeNamedConfig(name); err != nil {
+			return err
+		}
+	}
+
+	// Drop file values for env-only keys
+	if err := c.applyEnvOnlyKeys(); err != nil {
+		return err
+	}
+
+	// Merge values read from secret files
+	if err := c.loadSecretFiles(); err != nil {
+		return err
+	}
+
+	c.snapshotFiles()
+	return c.loadEnv()
+}
+
+// snapshotFiles records the loaded file layer. Environment binding must not be
+// configured yet, so AllSettings holds file values only.
+func (c *Config) snapshotFiles() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	origins := make(map[string]string, len(c.origins))
+	for k, v := range c.origins {
+		origins[k] = v
+	}
+	c.files = &fileLayer{
+		settings:   c.viper.AllSettings(),
+		origins:    origins,
+		configFile: c.viper.ConfigFileUsed(),
+	}
+}
+
+// restoreFiles loads the file layer from files without touching the filesystem,
+// then binds environment variables.
+func (c *Config) restoreFiles(files *fileLayer) error {
+	c.mu.Lock()
+	if err := c.viper.MergeConfigMap(deepCopyMap(files.settings)); err != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("failed to restore config files: %w", err)
+	}
+	if files.configFile != "" {
+		c.viper.SetConfigFile(files.configFile)
+	}
+	c.origins = make(map[string]string, len(files.origins))
+	for k, v := range files.origins {
+		c.origins[k] = v
+	}
+	c.files = files
+	c.mu.Unlock()
+
+	return c.loadEnv()
+}
+
+// deepCopyMap copies m and the maps and lists nested in it, since viper merges
+// nested maps by reference.
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+// deepCopyValue copies maps and lists in v; other values are returned as is.
+func deepCopyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = deepCopyValue(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// rebuild replaces the underlying viper with a fresh instance: the file layer from
+// the last accepted load is restored (the filesystem is not read, so changes a
+// validated reload rejected stay out), then MergeConfigMap layers and Set overrides
+// are replayed in order. Loaders are not run again; their layers are replayed too.
+// Changes made directly on Viper() are not preserved. Caller must hold c.mu for writing.
+func (c *Config) rebuild() error {
+	next, err := c.build(false)
+	if err != nil {
+		return err
+	}
+	c.swap(next)
+	return nil
+}
+
+// build loads a fresh Config and replays the runtime layers, leaving c untouched.
+// With reload, files are read from disk again and Options.Loaders run again on the
+// fresh Config instead of their previous layers being replayed; otherwise the file
+// layer of the last accepted load is restored. Caller must hold c.mu.
+func (c *Config) build(reload bool) (*Config, error) {
+	next := &Config{
+		viper:     newViper(&c.opts),
+		envPrefix: c.envPrefix,
+		sliceSep:  c.sliceSep,
+		keyCase:   make(map[string]string, len(c.keyCase)),
+		opts:      c.opts,
+		source:    c.source,
+
+		envBindings: c.envBindings,
+	}
+	for k, v := range c.keyCase {
+		next.keyCase[k] = v
+	}
+	if reload || c.files == nil {
+		if err := next.loadFiles(); err != nil {
+			return nil, err
+		}
+	} else if err := next.restoreFiles(c.files); err != nil {
+		return nil, err
+	}
+
+	merged, overrides := c.merged, c.overrides
+	if reload {
+		if err := next.runLoaders(); err != nil {
+			return nil, err
+		}
+		merged, overrides = c.merged[c.loaderMerged:], c.overrides[c.loaderOverrides:]
+	} else {
+		next.loaderMerged, next.loaderOverrides = c.loaderMerged, c.loaderOverrides
+	}
+
+	for _, m := range merged {
+		if err := next.viper.MergeConfigMap(m); err != nil {
+			return nil, fmt.Errorf("failed to merge config map: %w", err)
+		}
+		next.recordOrigin("", m, OriginMerge)
+	}
+	for _, o := range overrides {
+		next.viper.Set(o.key, o.value)
+	}
+	next.merged = append(next.merged, merged...)
+	next.overrides = append(next.overrides, overrides...)
+	return next, nil
+}
+
+// swap installs the state loaded by build: viper, origins, key case, and runtime
+// layers. Readers holding c.mu never see a partially loaded config, since next is
+// fully loaded before it is swapped in. Caller must hold c.mu for writing.
+func (c *Config) swap(next *Config) {
+	c.viper = next.viper
+	c.origins = next.origins
+	c.keyCase = next.keyCase
+	c.envSlices = next.envSlices
+	c.files = next.files
+	c.merged = next.merged
+	c.overrides = next.overrides
+	c.loaderMerged = next.loaderMerged
+	c.loaderOverrides = next.loaderOverrides
+	c.revision.Add(1)
+}
+
+// loadConfig loads the base configuration file, or the in-memory source if set.
 func (c *Config) loadConfig() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.source != nil {
+		return c.loadSource()
+	}
+
 	if err := c.viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			return nil
@@ -170,28 +527,78 @@ func (c *Config) loadConfig() error {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
 
-	return nil
+	c.recordFileKeyCase(c.viper.ConfigFileUsed())
+	c.recordOrigin("", c.viper.AllSettings(), OriginFile+":"+c.viper.ConfigFileUsed())
+	return c.relayerBaseImports()
 }
 
 // loadEnvConfig loads environment-specific configuration.
 // It looks for files like config.production.yaml
 func (c *Config) loadEnvConfig(env string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	if err := c.mergeNamedConfig(fmt.Sprintf("%s.%s", c.opts.ConfigName, env)); err != nil {
+		return fmt.Errorf("failed to read env config: %w", err)
+	}
+	return nil
+}
 
-	envConfigName := fmt.Sprintf("%s.%s", c.viper.ConfigFileUsed(), env)
-	c.viper.SetConfigName(envConfigName)
+// pathsAfterBase returns the search paths following the directory the base config
+// was read from, or nil if it came from an in-memory source or wasn't found.
+func (c *Config) pathsAfterBase() []string {
+	c.mu.RLock()
+	used := c.viper.ConfigFileUsed()
+	c.mu.RUnlock()
 
-	if err := c.viper.MergeInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			return nil
+	if c.source != nil || used == "" {
+		return nil
+	}
+
+	paths := c.opts.searchPaths()
+	for i, dir := range paths {
+		if abs, err := filepath.Abs(dir); err == nil && abs == filepath.Dir(used) {
+			return paths[i+1:]
 		}
-		return fmt.Errorf("failed to read env config: %w", err)
 	}
+	return nil
+}
 
+// mergeNamedConfig merges the config file {name}.{ext} from each search path, in
+// order, over the current values.
+func (c *Config) mergeNamedConfig(name string) error {
+	for _, dir := range c.opts.searchPaths() {
+		if err := c.mergeConfigFile(dir, name); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// mergeConfigFile merges the config file {name}.{ext} in dir, and the files it
+// imports, over the current values. The file type is detected from its extension.
+// Missing files are ignored.
+func (c *Config) mergeConfigFile(dir, name string) error {
+	path, ok := findConfigFile(dir, name)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.mergeFile(path, nil)
+}
+
+// findConfigFile returns the first existing {name}.{ext} in dir, trying
+// extensions in viper.SupportedExts order.
+func findConfigFile(dir, name string) (string, bool) {
+	for _, ext := range viper.SupportedExts {
+		path := filepath.Join(dir, name+"."+ext)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
 // Get returns a configuration value as interface{}
 func (c *Config) Get(key string) interface{} {
 	c.mu.RLock()
@@ -234,18 +641,223 @@ func (c *Config) GetDuration(key string) time.Duration {
 	return c.viper.GetDuration(key)
 }
 
-// GetStringSlice returns a configuration value as []string
+// GetStringSlice returns a configuration value as []string.
+// When the key is provided by a single environment variable containing the
+// SliceDelimiter, the value is split on it and each element is trimmed.
 func (c *Config) GetStringSlice(key string) []string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+
+	if c.sliceSep != "" {
+		// Only split when the effective value is the raw env string (Set/flags take precedence)
+		raw, ok := os.LookupEnv(c.envKey(key))
+		if !ok {
+			_, raw, ok = c.boundEnv(key)
+		}
+		if ok && strings.Contains(raw, c.sliceSep) {
+			if v, isStr := c.viper.Get(key).(string); isStr && v == raw {
+				return splitTrim(raw, c.sliceSep)
+			}
+		}
+	}
 	return c.viper.GetStringSlice(key)
 }
 
-// GetIntSlice returns a configuration value as []int
+// splitTrim splits s on sep, trimming whitespace and dropping empty elements.
+func splitTrim(s, sep string) []string {
+	parts := strings.Split(s, sep)
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// GetIntSlice returns a configuration value as []int.
+// Besides lists from config files, string values (typically a single environment
+// variable) are accepted as a JSON array ("[8080,8081]") or split on the
+// SliceDelimiter ("8080,8081"). Elements that aren't valid integers are skipped;
+// use GetIntSliceE to have them reported instead.
 func (c *Config) GetIntSlice(key string) []int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.viper.GetIntSlice(key)
+
+	ints, _ := c.intSlice(key)
+	return ints
+}
+
+// intSlice converts the value of key to []int, returning the valid elements and
+// the raw text of any invalid ones. Caller must hold c.mu.
+func (c *Config) intSlice(key string) (ints []int, invalid []string) {
+	val := c.viper.Get(key)
+
+	var elems []interface{}
+	switch v := val.(type) {
+	case nil:
+		return []int{}, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if strings.HasPrefix(s, "[") {
+			dec := json.NewDecoder(strings.NewReader(s))
+			dec.UseNumber()
+			if err := dec.Decode(&elems); err != nil {
+				return []int{}, []string{s}
+			}
+			break
+		}
+		for _, p := range splitTrim(s, c.sliceSep) {
+			elems = append(elems, p)
+		}
+	default:
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			elems = []interface{}{val}
+			break
+		}
+		for i := 0; i < rv.Len(); i++ {
+			elems = append(elems, rv.Index(i).Interface())
+		}
+	}
+
+	ints = make([]int, 0, len(elems))
+	for _, e := range elems {
+		n, err := toInt(e)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprint(e))
+			continue
+		}
+		ints = append(ints, n)
+	}
+	return ints, invalid
+}
+
+// toInt converts a slice element to int. Strings must be plain base-10 integers,
+// so "010" is 10 rather than octal.
+func toInt(v interface{}) (int, error) {
+	switch e := v.(type) {
+	case string:
+		return strconv.Atoi(strings.TrimSpace(e))
+	case json.Number:
+		return strconv.Atoi(e.String())
+	}
+	return cast.ToIntE(v)
+}
+
+// GetSlice returns a configuration value as []interface{}, for iterating lists
+// without a compile-time type. Returns nil if the key is missing, empty, or not a list.
+func (c *Config) GetSlice(key string) []interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, err := cast.ToSliceE(c.viper.Get(key))
+	if err != nil || len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+// GetMapSlice returns an array of tables (e.g. a YAML list of objects) as
+// []map[string]interface{}. Elements that are not maps are skipped.
+// Returns nil if the key is missing, empty, or not a list.
+//
+// Example:
+//
+//	// servers: [{host: a, port: 80}, {host: b, port: 81}]
+//	for _, s := range cfg.GetMapSlice("servers") {
+//	    addUpstream(cast.ToString(s["host"]), cast.ToInt(s["port"]))
+//	}
+func (c *Config) GetMapSlice(key string) []map[string]interface{} {
+	items := c.GetSlice(key)
+	if items == nil {
+		return nil
+	}
+
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		m, err := cast.ToStringMapE(item)
+		if err != nil {
+			continue
+		}
+		result = append(result, m)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// GetIndexed returns the value at a dotted path that may step into lists with
+// numeric segments, e.g. "servers.0.host", which Get can't address. The path up to
+// the first numeric segment is read like Get (so environment variables and Set
+// apply), then the rest is navigated through lists by index and maps by key
+// (case-insensitively). ok is false if any segment is missing or out of range.
+//
+// Example:
+//
+//	// servers: [{host: a, port: 80}, {host: b, port: 81}]
+//	host, ok := cfg.GetIndexed("servers.1.host") // "b", true
+//	_, ok = cfg.GetIndexed("servers.5.host")     // nil, false
+func (c *Config) GetIndexed(path string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	segments := strings.Split(path, ".")
+	i := 0
+	for i < len(segments) && !isIndexSegment(segments[i]) {
+		i++
+	}
+	if i == 0 {
+		return nil, false // A path can't start with an index
+	}
+
+	base := strings.Join(segments[:i], ".")
+	if !c.viper.IsSet(base) {
+		return nil, false
+	}
+	val := c.viper.Get(base)
+	for _, seg := range segments[i:] {
+		var ok bool
+		if val, ok = indexValue(val, seg); !ok {
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+// isIndexSegment reports whether a path segment is a list index.
+func isIndexSegment(seg string) bool {
+	_, err := strconv.ParseUint(seg, 10, 0)
+	return err == nil
+}
+
+// indexValue returns the element of a list at the numeric segment seg, or the
+// value of a map at key seg (matched case-insensitively if there's no exact key).
+func indexValue(val interface{}, seg string) (interface{}, bool) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(seg)
+		if err != nil || i < 0 || i >= rv.Len() {
+			return nil, false
+		}
+		return rv.Index(i).Interface(), true
+	case reflect.Map:
+		m, err := cast.ToStringMapE(val)
+		if err != nil {
+			return nil, false
+		}
+		if v, ok := m[seg]; ok {
+			return v, true
+		}
+		for k, v := range m {
+			if strings.EqualFold(k, seg) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
 }
 
 // GetStringMap returns a configuration value as map[string]interface{}
@@ -269,6 +881,59 @@ func (c *Config) GetStringMapStringSlice(key string) map[string][]string {
 	return c.viper.GetStringMapStringSlice(key)
 }
 
+// GetStringMapInt returns a configuration value as map[string]int.
+// Values that cannot be cast to int are skipped (the key is omitted from the result).
+func (c *Config) GetStringMapInt(key string) map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]int)
+	for k, v := range c.viper.GetStringMap(key) {
+		i, err := cast.ToIntE(v)
+		if err != nil {
+			continue
+		}
+		result[k] = i
+	}
+	return result
+}
+
+// GetStringMapBool returns a configuration value as map[string]bool.
+// Values that cannot be cast to bool are skipped (the key is omitted from the result).
+func (c *Config) GetStringMapBool(key string) map[string]bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]bool)
+	for k, v := range c.viper.GetStringMap(key) {
+		b, err := cast.ToBoolE(v)
+		if err != nil {
+			continue
+		}
+		result[k] = b
+	}
+	return result
+}
+
+// GetStringMapDuration returns a configuration value as map[string]time.Duration,
+// e.g. timeouts: {read: 5s, write: 10s}. Strings use time.ParseDuration syntax.
+// Values that cannot be cast to a duration are skipped (the key is omitted from the result);
+// use GetStringMapDurationE to surface them as an error.
+func (c *Config) GetStringMapDuration(key string) map[string]time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]time.Duration)
+	for k, v := range c.viper.GetStringMap(key) {
+		d, err := cast.ToDurationE(v)
+		if err != nil {
+			continue
+		}
+		result[k] = d
+	}
+	return result
+}
+
 // Unmarshal unmarshals configuration into a struct.
 // Use this for type-safe configuration handling.
 func (c *Config) Unmarshal(rawVal interface{}) error {
@@ -292,6 +957,8 @@ func (c *Config) IsSet(key string) bool {
 }
 
 // IsSetOrEnv returns whether a key is set in configuration or as environment variable.
+// The environment variable name honours the configured EnvPrefix (e.g. APP_DATABASE_HOST);
+// variables bound with BindEnv are checked too.
 func (c *Config) IsSetOrEnv(key string) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -300,11 +967,76 @@ func (c *Config) IsSetOrEnv(key string) bool {
 		return true
 	}
 
-	envKey := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
-	_, exists := os.LookupEnv(envKey)
+	if _, exists := os.LookupEnv(c.envKey(key)); exists {
+		return true
+	}
+	_, _, exists := c.boundEnv(key)
 	return exists
 }
 
+// BindEnv binds key to the given environment variables, checked in order; the first
+// one that is set (and non-empty) provides the value. The names are used exactly as
+// given: explicit bindings bypass EnvPrefix and the key replacer, so standard
+// variables like PORT or DATABASE_URL can feed any key. With no names, key is bound
+// to its usual prefixed name (e.g. APP_DATABASE_URL), even if AutoEnvEnabled is off.
+//
+// A variable matching the key through AutomaticEnv (EnvPrefix and key replacer) still
+// takes precedence over an explicit binding. Bindings survive Unset and reloads.
+//
+// Example:
+//
+//	// Cloud Run and Heroku set PORT; prefer APP_SERVER_PORT when both exist
+//	if err := cfg.BindEnv("server.port", "PORT"); err != nil {
+//	    return err
+//	}
+//	cfg.BindEnv("database.url", "DATABASE_URL", "POSTGRES_URL")
+func (c *Config) BindEnv(key string, envVars ...string) error {
+	if key == "" {
+		return fmt.Errorf("BindEnv requires a key")
+	}
+	if len(envVars) == 0 {
+		envVars = []string{c.envKey(key)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := strings.ToLower(key)
+	if err := c.viper.BindEnv(append([]string{k}, envVars...)...); err != nil {
+		return err
+	}
+
+	// Copy on write: rebuilt candidates share the map while they load
+	bindings := make(map[string][]string, len(c.envBindings)+1)
+	for bk, vars := range c.envBindings {
+		bindings[bk] = vars
+	}
+	bindings[k] = append([]string(nil), envVars...)
+	c.envBindings = bindings
+	c.revision.Add(1)
+	return nil
+}
+
+// boundEnv returns the first non-empty variable bound to key with BindEnv, matching
+// viper, which ignores empty values. Caller must hold c.mu.
+func (c *Config) boundEnv(key string) (name, value string, ok bool) {
+	for _, name := range c.envBindings[strings.ToLower(key)] {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			return name, value, true
+		}
+	}
+	return "", "", false
+}
+
+// envKey converts a config key to its environment variable name, including the prefix.
+func (c *Config) envKey(key string) string {
+	envKey := strings.ReplaceAll(key, ".", "_")
+	if c.envPrefix != "" {
+		envKey = c.envPrefix + "_" + envKey
+	}
+	return strings.ToUpper(envKey)
+}
+
 // AllSettings returns all configuration settings.
 func (c *Config) AllSettings() map[string]interface{} {
 	c.mu.RLock()
@@ -317,18 +1049,112 @@ func (c *Config) Set(key string, value interface{}) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.viper.Set(key, value)
+	c.revision.Add(1)
+	c.removeOverrides(key)
+	c.overrides = append(c.overrides, override{key: key, value: value})
+
+	// Remember the original spelling of the key path and any nested map keys
+	path := ""
+	for _, part := range strings.Split(key, ".") {
+		c.recordKeyCase(path, map[string]interface{}{part: nil})
+		path = joinKeyPath(path, strings.ToLower(part))
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		c.recordKeyCase(path, m)
+	}
 }
 
-// Watch registers a callback to be called when configuration changes.
+// MergeConfigMap merges a map of settings over the current configuration.
+// Nested maps are merged key by key, so only the provided keys are overridden.
+func (c *Config) MergeConfigMap(settings map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.viper.MergeConfigMap(settings); err != nil {
+		return err
+	}
+	c.merged = append(c.merged, settings)
+	c.revision.Add(1)
+	c.recordKeyCase("", settings)
+	c.recordOrigin("", settings, OriginMerge)
+	return nil
+}
+
+// Unset removes a runtime override previously applied with Set, including overrides
+// of nested keys below it. Afterwards the key resolves from files, environment, and
+// MergeConfigMap layers again, so IsSet no longer reports a lingering value.
+//
+// Viper has no delete operation, so Unset rebuilds the underlying viper instance
+// from the file layer of the last accepted load and replays the remaining runtime
+// layers. Files are not read again, so Unset can't fail on unrelated I/O or pick up
+// a change WatchValidated rejected. Changes made directly on Viper() are lost.
+//
+// Example:
+//
+//	cfg.Set("runtime.maintenance", true)
+//	// ...
+//	if err := cfg.Unset("runtime.maintenance"); err != nil {
+//	    log.Printf("unset failed: %v", err)
+//	}
+func (c *Config) Unset(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeOverrides(key)
+	return c.rebuild()
+}
+
+// removeOverrides drops recorded overrides for key and its nested keys. Caller must hold c.mu.
+func (c *Config) removeOverrides(key string) {
+	k := strings.ToLower(key)
+	kept := c.overrides[:0]
+	loaderKept := 0
+	for i, o := range c.overrides {
+		ok := strings.ToLower(o.key)
+		if ok == k || strings.HasPrefix(ok, k+".") {
+			continue
+		}
+		if i < c.loaderOverrides {
+			loaderKept++
+		}
+		kept = append(kept, o)
+	}
+	c.overrides = kept
+	c.loaderOverrides = loaderKept
+}
+
+// Watch registers a callback to be called after WatchConfig applies a configuration change.
 func (c *Config) Watch(callback func()) {
-	c.viper.OnConfigChange(func(in fsnotify.Event) {
-		callback()
-	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.watchers = append(c.watchers, callback)
 }
 
-// WatchConfig enables watching for configuration file changes.
+// WatchConfig enables watching for configuration file changes. On each change the
+// configuration is reloaded in full (files, loaders, then MergeConfigMap layers and
+// Set overrides) into a fresh instance, which replaces the live one atomically once
+// it loaded successfully; readers never observe a half-applied reload. Callbacks
+// registered with Watch run afterwards. Failed reloads keep the previous configuration
+// and are passed to Options.OnReloadError. Calling it again, or after WatchValidated,
+// has no effect. Only the base config file is watched, not imported files.
 func (c *Config) WatchConfig() {
-	c.viper.WatchConfig()
+	if !c.startWatching() {
+		return
+	}
+
+	c.watchFile(func() {
+		if err := c.reload(nil); err != nil {
+			c.reportReloadError(err)
+			return
+		}
+
+		c.mu.RLock()
+		watchers := append([]func(){}, c.watchers...)
+		c.mu.RUnlock()
+		for _, cb := range watchers {
+			cb()
+		}
+	})
 }
 
 // Viper returns the underlying Viper instance for advanced operations.
@@ -2,9 +2,11 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -99,3 +101,222 @@ func TestGlobalConfig(t *testing.T) {
 	SetGlobal(cfg)
 	assert.Equal(t, cfg, Global())
 }
+
+func TestGetStringMapInt(t *testing.T) {
+	cfg, err := New(nil)
+	require.NoError(t, err)
+	cfg.Set("quotas", map[string]interface{}{
+		"free": 100,
+		"pro":  "1000",
+		"bad":  "unlimited",
+	})
+
+	quotas := cfg.GetStringMapInt("quotas")
+	assert.Equal(t, map[string]int{"free": 100, "pro": 1000}, quotas)
+}
+
+func TestGetStringMapBool(t *testing.T) {
+	cfg, err := New(nil)
+	require.NoError(t, err)
+	cfg.Set("features", map[string]interface{}{
+		"search": true,
+		"export": "false",
+		"bad":    "maybe",
+	})
+
+	features := cfg.GetStringMapBool("features")
+	assert.Equal(t, map[string]bool{"search": true, "export": false}, features)
+}
+
+func TestGetStringMapDuration(t *testing.T) {
+	cfg, err := New(nil)
+	require.NoError(t, err)
+	cfg.Set("timeouts", map[string]interface{}{
+		"read":  "5s",
+		"write": "1m30s",
+		"bad":   "soon",
+	})
+
+	timeouts := cfg.GetStringMapDuration("timeouts")
+	assert.Equal(t, map[string]time.Duration{"read": 5 * time.Second, "write": 90 * time.Second}, timeouts)
+}
+
+func TestNewAutoEnvCanBeDisabled(t *testing.T) {
+	t.Setenv("APP_SERVER_PORT", "9090")
+	t.Setenv("APP_DEBUG", "true")
+
+	cfg, err := New(&Options{EnvPrefix: "APP"})
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+
+	cfg, err = New(&Options{EnvPrefix: "APP", AutoEnvEnabled: Bool(false)})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.GetInt("server.port"))
+	assert.Equal(t, "", cfg.Origin("server.port"))
+
+	// Without the key replacer only top-level keys map to variables
+	cfg, err = New(&Options{EnvPrefix: "APP", LookupsEnv: Bool(false)})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.GetInt("server.port"))
+	assert.True(t, cfg.GetBool("debug"))
+	assert.Equal(t, "", cfg.Origin("server.port"))
+}
+
+func TestIsSetOrEnvWithPrefix(t *testing.T) {
+	t.Setenv("APP_CACHE_URL", "redis://localhost")
+
+	// Plain viper without AutomaticEnv so the env fallback path is exercised
+	cfg := &Config{viper: viper.New(), envPrefix: "APP"}
+	assert.True(t, cfg.IsSetOrEnv("cache.url"))
+	assert.False(t, cfg.IsSetOrEnv("cache.host"))
+
+	t.Setenv("CACHE_HOST", "unprefixed")
+	assert.False(t, cfg.IsSetOrEnv("cache.host"))
+}
+
+func TestGetStringSliceFromEnv(t *testing.T) {
+	t.Setenv("APP_CORS_ORIGINS", "a.com, b.com,,c.com")
+
+	cfg, err := New(&Options{EnvPrefix: "APP"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.com", "b.com", "c.com"}, cfg.GetStringSlice("cors.origins"))
+}
+
+func TestGetIntSliceFromEnv(t *testing.T) {
+	t.Setenv("APP_PORTS", "8080, 8081,,x")
+	t.Setenv("APP_ADMIN_PORTS", "[9090, 9091]")
+
+	cfg, err := New(&Options{EnvPrefix: "APP"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{8080, 8081}, cfg.GetIntSlice("ports"))
+	assert.Equal(t, []int{9090, 9091}, cfg.GetIntSlice("admin.ports"))
+	assert.Equal(t, []int{}, cfg.GetIntSlice("missing"))
+
+	cfg.Set("ports", []interface{}{1, "2", 3.0})
+	assert.Equal(t, []int{1, 2, 3}, cfg.GetIntSlice("ports"))
+}
+
+func TestGetStringSliceCustomDelimiter(t *testing.T) {
+	t.Setenv("APP_HOSTS", "a;b")
+
+	cfg, err := New(&Options{EnvPrefix: "APP", SliceDelimiter: ";"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("hosts"))
+}
+
+func TestUnsetRestoresFileValue(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: 8080\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+
+	cfg.Set("server.port", 9090)
+	cfg.Set("feature.enabled", true)
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+
+	require.NoError(t, cfg.Unset("server.port"))
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	assert.True(t, cfg.GetBool("feature.enabled"))
+
+	require.NoError(t, cfg.Unset("feature"))
+	assert.False(t, cfg.IsSet("feature.enabled"))
+}
+
+func TestUnsetKeepsMergedMaps(t *testing.T) {
+	cfg, err := New(&Options{ConfigPath: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.MergeConfigMap(map[string]interface{}{"app": map[string]interface{}{"name": "merged"}}))
+	cfg.Set("app.name", "override")
+
+	require.NoError(t, cfg.Unset("app.name"))
+	assert.Equal(t, "merged", cfg.GetString("app.name"))
+}
+
+func TestBindEnv(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: 8080\n")
+	t.Setenv("PORT", "9090")
+	t.Setenv("ALLOWED_HOSTS", "a.com, b.com")
+
+	cfg, err := New(&Options{ConfigPath: dir, EnvPrefix: "APP"})
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+	// Explicit names bypass the prefix; the first non-empty variable wins
+	require.NoError(t, cfg.BindEnv("server.port", "APP_PORT_OVERRIDE", "PORT"))
+	require.NoError(t, cfg.BindEnv("cors.hosts", "ALLOWED_HOSTS"))
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	assert.Equal(t, "env:PORT", cfg.Origin("server.port"))
+	assert.True(t, cfg.IsSetOrEnv("cors.hosts"))
+	assert.Equal(t, []string{"a.com", "b.com"}, cfg.GetStringSlice("cors.hosts"))
+
+	// The prefixed AutomaticEnv name still takes precedence
+	t.Setenv("APP_SERVER_PORT", "7070")
+	assert.Equal(t, 7070, cfg.GetInt("server.port"))
+
+	// Bindings survive a rebuild
+	cfg.Set("other", true)
+	require.NoError(t, cfg.Unset("other"))
+	t.Setenv("APP_SERVER_PORT", "")
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+
+	assert.Error(t, cfg.BindEnv(""))
+}
+
+func TestGetIndexed(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "servers:\n  - host: a\n    Port: 80\n  - host: b\n    tags: [x, y]\nlimits:\n  \"0\": zero\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		want interface{}
+		ok   bool
+	}{
+		{"servers.1.host", "b", true},
+		{"servers.0.port", 80, true},
+		{"servers.1.tags.1", "y", true},
+		{"limits.0", "zero", true},
+		{"servers.2.host", nil, false},
+		{"servers.0.missing", nil, false},
+		{"servers.0.host.0", nil, false},
+		{"missing.0", nil, false},
+		{"0.host", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := cfg.GetIndexed(tt.path)
+		assert.Equal(t, tt.ok, ok, tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+
+	// Values from Set are navigated too
+	cfg.Set("hosts", []string{"h0", "h1"})
+	got, ok := cfg.GetIndexed("hosts.1")
+	assert.True(t, ok)
+	assert.Equal(t, "h1", got)
+}
+
+func TestUnsetDoesNotReadFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: 8080\n")
+	writeConfigFile(t, dir, "db_password", "s3cret")
+
+	cfg, err := New(&Options{
+		ConfigPath:  dir,
+		SecretFiles: map[string]string{"database.password": filepath.Join(dir, "db_password")},
+	})
+	require.NoError(t, err)
+
+	// Unrelated files changing or disappearing don't affect Unset
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: [\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "db_password")))
+
+	cfg.Set("server.port", 9090)
+	require.NoError(t, cfg.Unset("server.port"))
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	assert.Equal(t, "s3cret", cfg.GetString("database.password"))
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Environment names a deployment environment. It selects the environment-specific
+// config overlay ({ConfigName}.{Env}.{ext}) via Options.Env.
+// Custom environments are allowed, e.g. config.Environment("qa").
+type Environment string
+
+// Well-known environments.
+const (
+	Development Environment = "development"
+	Staging     Environment = "staging"
+	Production  Environment = "production"
+)
+
+// environmentAliases maps accepted spellings to well-known environments.
+var environmentAliases = map[string]Environment{
+	"development": Development,
+	"dev":         Development,
+	"develop":     Development,
+	"staging":     Staging,
+	"stage":       Staging,
+	"production":  Production,
+	"prod":        Production,
+}
+
+// ParseEnvironment parses a well-known environment name, case-insensitively and
+// ignoring surrounding whitespace. Common short forms are accepted ("dev", "stage",
+// "prod"). Any other value is an error, so typos fail at startup instead of silently
+// loading no overlay.
+//
+// Example:
+//
+//	env, err := config.ParseEnvironment(os.Getenv("APP_ENV"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	cfg, err := config.New(&config.Options{ConfigPath: "./config", Env: env})
+func ParseEnvironment(s string) (Environment, error) {
+	if env, ok := environmentAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return env, nil
+	}
+	return "", fmt.Errorf("unknown environment %q (expected %s, %s, or %s)", s, Development, Staging, Production)
+}
+
+// String returns the environment name.
+func (e Environment) String() string {
+	return string(e)
+}
+
+// Environment returns the environment the config was loaded for (Options.Env).
+func (c *Config) Environment() Environment {
+	return c.opts.Env
+}
+
+// IsProduction reports whether the config was loaded for the Production environment.
+func (c *Config) IsProduction() bool {
+	return c.opts.Env == Production
+}
+
+// IsDevelopment reports whether the config was loaded for the Development environment.
+func (c *Config) IsDevelopment() bool {
+	return c.opts.Env == Development
+}
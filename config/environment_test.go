@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Environment
+	}{
+		{"production", Production},
+		{"PROD", Production},
+		{" staging ", Staging},
+		{"stage", Staging},
+		{"dev", Development},
+		{"Development", Development},
+	}
+	for _, tt := range tests {
+		got, err := ParseEnvironment(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := ParseEnvironment("produciton")
+	assert.ErrorContains(t, err, `unknown environment "produciton"`)
+	_, err = ParseEnvironment("")
+	assert.Error(t, err)
+}
+
+func TestConfigEnvironmentHelpers(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "debug: true\n")
+	writeConfigFile(t, dir, "config.production.yaml", "debug: false\n")
+
+	cfg, err := New(&Options{ConfigPath: dir, Env: Production})
+	require.NoError(t, err)
+	assert.Equal(t, Production, cfg.Environment())
+	assert.True(t, cfg.IsProduction())
+	assert.False(t, cfg.IsDevelopment())
+	assert.False(t, cfg.GetBool("debug"), "production overlay should be loaded")
+
+	cfg, err = New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+	assert.False(t, cfg.IsProduction())
+	assert.False(t, cfg.IsDevelopment())
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// applyEnvOnlyKeys removes values for Options.EnvOnlyKeys that came from config
+// files, so those keys resolve only from environment variables (or later layers
+// such as secret files, loaders, and Set). With Options.EnvOnlyStrict, a config
+// file setting an env-only key is an error instead.
+//
+// Viper has no delete, so each key is merged as nil into the file layer, which
+// makes it unset while environment variables still take precedence.
+func (c *Config) applyEnvOnlyKeys() error {
+	if len(c.opts.EnvOnlyKeys) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cleared := make(map[string]interface{})
+	var violations []string
+	for _, key := range c.opts.EnvOnlyKeys {
+		k := strings.ToLower(key)
+		files := c.fileOrigins(k)
+		if len(files) == 0 {
+			continue
+		}
+		if c.opts.EnvOnlyStrict {
+			violations = append(violations, fmt.Sprintf("%s (set in %s)", key, strings.Join(files, ", ")))
+			continue
+		}
+		// Clear each leaf: merging nil over a nested map leaves it in place
+		for path := range c.origins {
+			if path == k || strings.HasPrefix(path, k+".") {
+				setNested(cleared, strings.Split(path, "."), nil)
+				delete(c.origins, path)
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return fmt.Errorf("env-only keys must not be set in config files: %s", strings.Join(violations, "; "))
+	}
+	if len(cleared) == 0 {
+		return nil
+	}
+	if err := c.viper.MergeConfigMap(cleared); err != nil {
+		return fmt.Errorf("failed to clear env-only keys: %w", err)
+	}
+	return nil
+}
+
+// fileOrigins returns the config files that set key or any key nested below it.
+// Caller must hold c.mu.
+func (c *Config) fileOrigins(key string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for path, origin := range c.origins {
+		if path != key && !strings.HasPrefix(path, key+".") && !strings.HasPrefix(key, path+".") {
+			continue
+		}
+		file, ok := strings.CutPrefix(origin, OriginFile+":")
+		if !ok || seen[file] {
+			continue
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
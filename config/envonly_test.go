@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvOnlyKeysDropsFileValues(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "database:\n  host: localhost\n  password: committed\napi:\n  token: abc\n")
+
+	cfg, err := New(&Options{ConfigPath: dir, EnvPrefix: "APP", EnvOnlyKeys: []string{"database.password", "api"}})
+	require.NoError(t, err)
+
+	assert.False(t, cfg.IsSet("database.password"))
+	assert.Equal(t, "", cfg.GetString("database.password"))
+	assert.False(t, cfg.IsSet("api.token"))
+	assert.Equal(t, "localhost", cfg.GetString("database.host"))
+	assert.Equal(t, "", cfg.Origin("database.password"))
+
+	// Runtime Set is still allowed
+	cfg.Set("database.password", "runtime")
+	assert.Equal(t, "runtime", cfg.GetString("database.password"))
+}
+
+func TestEnvOnlyKeysFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "database:\n  password: committed\n")
+	t.Setenv("APP_DATABASE_PASSWORD", "from-env")
+
+	cfg, err := New(&Options{ConfigPath: dir, EnvPrefix: "APP", EnvOnlyKeys: []string{"database.password"}})
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.GetString("database.password"))
+}
+
+func TestEnvOnlyKeysStrict(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "database:\n  password: committed\n")
+
+	_, err := New(&Options{ConfigPath: dir, EnvOnlyKeys: []string{"database.password"}, EnvOnlyStrict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.password")
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envNestedSep separates nested field names inside an indexed list item variable,
+// e.g. APP_SERVERS_0_TLS__CERT_FILE -> tls.cert_file.
+const envNestedSep = "__"
+
+// applyEnvSlices builds the lists for Options.EnvSliceKeys from indexed environment
+// variables and sets them at environment precedence, replacing the whole list from
+// config files, secret files, and loaders. Keys with no matching variables are left
+// alone. Runtime Set still wins.
+//
+// For a key, variables are named {ENV_KEY}_{INDEX} for scalar items or
+// {ENV_KEY}_{INDEX}_{FIELD} for map items, where ENV_KEY is the key's usual
+// variable name (e.g. servers -> APP_SERVERS):
+//   - INDEX is a decimal without leading zeros; indexes must run from 0 without gaps
+//   - FIELD is lowercased and keeps its underscores (MAX_CONNS -> max_conns); "__"
+//     separates nested fields (TLS__CERT_FILE -> tls.cert_file)
+//   - Empty variables are ignored, as for other environment variables
+//
+// Caller must not hold c.mu.
+func (c *Config) applyEnvSlices() error {
+	if len(c.opts.EnvSliceKeys) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	environ := os.Environ()
+	applied := make(map[string]string)
+	for _, key := range c.opts.EnvSliceKeys {
+		k := strings.ToLower(key)
+		name := c.envKey(k)
+		list, err := envSlice(name+"_", environ)
+		if err != nil {
+			return fmt.Errorf("invalid indexed environment variables for %q: %w", key, err)
+		}
+		if list == nil {
+			continue
+		}
+		c.viper.Set(k, list)
+		applied[k] = OriginEnv + ":" + name + "_*"
+	}
+	c.envSlices = applied
+	return nil
+}
+
+// envSlice collects the variables in environ named {prefix}{INDEX} or
+// {prefix}{INDEX}_{FIELD} into a list, following the scheme described on
+// applyEnvSlices. Returns nil if no variable matches.
+func envSlice(prefix string, environ []string) ([]interface{}, error) {
+	// Sorted, so a nested field (TLS__CERT) deterministically replaces a scalar (TLS)
+	sorted := append([]string(nil), environ...)
+	sort.Strings(sorted)
+
+	items := make(map[int]interface{})
+	maxIndex := -1
+	for _, kv := range sorted {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || value == "" {
+			continue
+		}
+		rest, ok := strings.CutPrefix(strings.ToUpper(name), prefix)
+		if !ok {
+			continue
+		}
+		idx, field, hasField := strings.Cut(rest, "_")
+		index, ok := parseEnvIndex(idx)
+		if !ok {
+			continue // Not an indexed variable, e.g. APP_SERVERS_MODE
+		}
+		if len(idx) > 1 && idx[0] == '0' {
+			return nil, fmt.Errorf("%s has a leading zero in its index", name)
+		}
+		if hasField && field == "" {
+			return nil, fmt.Errorf("%s has an empty field name", name)
+		}
+
+		existing, seen := items[index]
+		if !hasField {
+			if seen {
+				return nil, fmt.Errorf("%s mixes a scalar item with fields", name)
+			}
+			items[index] = value
+		} else {
+			item, isMap := existing.(map[string]interface{})
+			if seen && !isMap {
+				return nil, fmt.Errorf("%s mixes a scalar item with fields", name)
+			}
+			if !seen {
+				item = make(map[string]interface{})
+				items[index] = item
+			}
+			path := strings.Split(strings.ToLower(field), envNestedSep)
+			for _, p := range path {
+				if p == "" {
+					return nil, fmt.Errorf("%s has an empty field name", name)
+				}
+			}
+			setNested(item, path, value)
+		}
+		if index > maxIndex {
+			maxIndex = index
+		}
+	}
+
+	if maxIndex < 0 {
+		return nil, nil
+	}
+	list := make([]interface{}, 0, len(items))
+	for i := 0; i <= maxIndex; i++ {
+		item, ok := items[i]
+		if !ok {
+			return nil, fmt.Errorf("missing index %d (%s%d_*), indexes must start at 0 without gaps", i, prefix, i)
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// parseEnvIndex parses a list index made only of decimal digits.
+func parseEnvIndex(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
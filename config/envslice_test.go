@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSliceKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "servers:\n  - host: file-host\n    port: 1\n")
+	t.Setenv("APP_SERVERS_0_HOST", "a.internal")
+	t.Setenv("APP_SERVERS_0_PORT", "8080")
+	t.Setenv("APP_SERVERS_0_MAX_CONNS", "10")
+	t.Setenv("APP_SERVERS_0_TLS__CERT_FILE", "/certs/a.pem")
+	t.Setenv("APP_SERVERS_1_HOST", "b.internal")
+	t.Setenv("APP_SERVERS_MODE", "ignored")
+	t.Setenv("APP_HOSTS_0", "x.com")
+	t.Setenv("APP_HOSTS_1", "y.com")
+
+	cfg, err := New(&Options{ConfigPath: dir, EnvPrefix: "APP", EnvSliceKeys: []string{"servers", "hosts", "missing"}})
+	require.NoError(t, err)
+
+	type TLSConfig struct {
+		CertFile string `mapstructure:"cert_file"`
+	}
+	type ServerConfig struct {
+		Host     string    `mapstructure:"host"`
+		Port     int       `mapstructure:"port"`
+		MaxConns int       `mapstructure:"max_conns"`
+		TLS      TLSConfig `mapstructure:"tls"`
+	}
+
+	var servers []ServerConfig
+	require.NoError(t, cfg.UnmarshalKey("servers", &servers))
+	assert.Equal(t, []ServerConfig{
+		{Host: "a.internal", Port: 8080, MaxConns: 10, TLS: TLSConfig{CertFile: "/certs/a.pem"}},
+		{Host: "b.internal"},
+	}, servers)
+	assert.Equal(t, []string{"x.com", "y.com"}, cfg.GetStringSlice("hosts"))
+	assert.False(t, cfg.IsSet("missing"))
+	assert.Equal(t, "env:APP_SERVERS_*", cfg.Origin("servers"))
+
+	// Runtime Set still wins, and the env list comes back after Unset
+	cfg.Set("hosts", []string{"z.com"})
+	assert.Equal(t, []string{"z.com"}, cfg.GetStringSlice("hosts"))
+	require.NoError(t, cfg.Unset("hosts"))
+	assert.Equal(t, []string{"x.com", "y.com"}, cfg.GetStringSlice("hosts"))
+}
+
+func TestEnvSliceKeysInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"gap", map[string]string{"APP_SERVERS_0_HOST": "a", "APP_SERVERS_2_HOST": "c"}},
+		{"not from zero", map[string]string{"APP_SERVERS_1_HOST": "b"}},
+		{"leading zero", map[string]string{"APP_SERVERS_01_HOST": "a"}},
+		{"scalar and fields", map[string]string{"APP_SERVERS_0": "a", "APP_SERVERS_0_HOST": "a"}},
+		{"empty field", map[string]string{"APP_SERVERS_0_": "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := New(&Options{ConfigPath: t.TempDir(), EnvPrefix: "APP", EnvSliceKeys: []string{"servers"}})
+			assert.Error(t, err)
+		})
+	}
+}
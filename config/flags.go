@@ -0,0 +1,74 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// featuresKey is the config section holding feature flags.
+const featuresKey = "features"
+
+// Flags reads feature flags from the features section of a Config. Values are read
+// on every call, so reloads and runtime Set calls take effect immediately.
+//
+// A flag is either a bool or a map with an enabled switch and a tenant allow-list
+// for gradual rollouts:
+//
+//	features:
+//	  search: true
+//	  new_ui:
+//	    enabled: false
+//	    tenants: [t1, t2] # Enabled for these tenants only
+//
+// Environment variables override flags like any other key, e.g.
+// APP_FEATURES_SEARCH=false or APP_FEATURES_NEW_UI_TENANTS=t1,t2,t3.
+type Flags struct {
+	cfg *Config
+}
+
+// Flags returns the feature flags defined under the features key.
+//
+// Example:
+//
+//	if cfg.Flags().EnabledForTenant("new_ui", tenantID) {
+//	    return renderNewUI(c)
+//	}
+func (c *Config) Flags() *Flags {
+	return &Flags{cfg: c}
+}
+
+// Enabled reports whether the flag is switched on for everyone. Missing flags and
+// values that aren't valid bools are disabled.
+func (f *Flags) Enabled(name string) bool {
+	key := flagKey(name)
+	if _, ok := f.cfg.Get(key).(map[string]interface{}); ok {
+		return f.cfg.GetBool(key + ".enabled")
+	}
+	return f.cfg.GetBool(key)
+}
+
+// EnabledForTenant reports whether the flag is on for tenantID: either listed in the
+// flag's tenants or enabled for everyone.
+func (f *Flags) EnabledForTenant(name, tenantID string) bool {
+	if tenantID != "" && slices.Contains(f.cfg.GetStringSlice(flagKey(name)+".tenants"), tenantID) {
+		return true
+	}
+	return f.Enabled(name)
+}
+
+// All returns every flag defined under features with its Enabled state, e.g. for
+// a diagnostics endpoint. Tenant allow-lists are not reflected. Flags set only
+// through environment variables are not listed.
+func (f *Flags) All() map[string]bool {
+	section := f.cfg.GetStringMap(featuresKey)
+	flags := make(map[string]bool, len(section))
+	for name := range section {
+		flags[name] = f.Enabled(name)
+	}
+	return flags
+}
+
+// flagKey returns the config key of the flag name.
+func flagKey(name string) string {
+	return featuresKey + "." + strings.ToLower(name)
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", `features:
+  search: true
+  export: "false"
+  new_ui:
+    enabled: false
+    tenants: [t1, t2]
+  beta:
+    enabled: true
+`)
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+	flags := cfg.Flags()
+
+	assert.True(t, flags.Enabled("search"))
+	assert.True(t, flags.Enabled("Search"))
+	assert.False(t, flags.Enabled("export"))
+	assert.False(t, flags.Enabled("new_ui"))
+	assert.False(t, flags.Enabled("missing"))
+
+	assert.True(t, flags.EnabledForTenant("new_ui", "t1"))
+	assert.False(t, flags.EnabledForTenant("new_ui", "t3"))
+	assert.True(t, flags.EnabledForTenant("beta", "t3"))
+	assert.True(t, flags.EnabledForTenant("search", ""))
+
+	assert.Equal(t, map[string]bool{"search": true, "export": false, "new_ui": false, "beta": true}, flags.All())
+
+	cfg.Set("features.new_ui.enabled", true)
+	assert.True(t, flags.EnabledForTenant("new_ui", "t3"))
+}
+
+func TestFlagsFromEnv(t *testing.T) {
+	t.Setenv("APP_FEATURES_SEARCH", "false")
+	t.Setenv("APP_FEATURES_NEW_UI_TENANTS", "t1,t3")
+
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "features:\n  search: true\n  new_ui:\n    tenants: [t1]\n")
+
+	cfg, err := New(&Options{ConfigPath: dir, EnvPrefix: "APP"})
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Flags().Enabled("search"))
+	assert.True(t, cfg.Flags().EnabledForTenant("new_ui", "t3"))
+}
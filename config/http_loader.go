@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/util"
+	"github.com/spf13/viper"
+)
+
+// HTTPLoaderOptions configures the remote HTTP loader.
+type HTTPLoaderOptions struct {
+	// Headers are added to every request (e.g. Authorization) (default: nil)
+	Headers map[string]string
+	// Timeout bounds each fetch (default: 10s)
+	Timeout time.Duration
+	// CacheTTL is how long fetched data is reused before refetching (default: 0 = always fetch)
+	CacheTTL time.Duration
+	// Format is the payload format: "json" or "yaml" (default: inferred from Content-Type, then "json")
+	Format string
+	// FailSoft keeps the last cached data when the endpoint is unreachable (default: false)
+	// If no cached data exists, the fetch error is still returned.
+	FailSoft bool
+	// Client is the HTTP client to use (default: a client with Timeout)
+	Client *http.Client
+}
+
+// httpCache holds the last successful remote payload for a loader.
+type httpCache struct {
+	mu        sync.Mutex
+	settings  map[string]interface{}
+	fetchedAt time.Time
+}
+
+// get returns the cached payload, if any, and whether it is younger than ttl.
+func (h *httpCache) get(ttl time.Duration) (settings map[string]interface{}, fresh bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.settings, h.settings != nil && ttl > 0 && time.Since(h.fetchedAt) < ttl
+}
+
+// set stores a freshly fetched payload.
+func (h *httpCache) set(settings map[string]interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.settings = settings
+	h.fetchedAt = time.Now()
+}
+
+// HTTPLoader returns a Loader that fetches JSON/YAML configuration from a URL
+// and merges it over the already-loaded values via MergeConfigMap.
+//
+// Concurrent loads (e.g. several Configs built from the same loader, or reloads
+// racing at startup) share one in-flight fetch through util.Once, and all of them
+// receive its result or error instead of each hitting the endpoint.
+//
+// Example:
+//
+//	cfg, err := config.New(&config.Options{
+//	    Loaders: []config.Loader{
+//	        config.HTTPLoader("https://config.internal/app.json", config.HTTPLoaderOptions{
+//	            Headers:  map[string]string{"Authorization": "Bearer " + token},
+//	            Timeout:  5 * time.Second,
+//	            CacheTTL: time.Minute,
+//	            FailSoft: true,
+//	        }),
+//	    },
+//	})
+func HTTPLoader(url string, opts HTTPLoaderOptions) Loader {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: opts.Timeout}
+	}
+
+	cache := &httpCache{}
+	fetches := &util.Once[string, map[string]interface{}]{}
+
+	return func(cfg *Config) error {
+		settings, err := fetches.GetOrLoad(url, func() (map[string]interface{}, error) {
+			// Serve from cache while fresh
+			cached, fresh := cache.get(opts.CacheTTL)
+			if fresh {
+				return cached, nil
+			}
+
+			settings, err := fetchRemoteConfig(url, opts)
+			if err != nil {
+				if opts.FailSoft && cached != nil {
+					return cached, nil
+				}
+				return nil, err
+			}
+			cache.set(settings)
+			return settings, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Copy: the payload is shared with other loads, and viper merges nested maps by reference
+		return cfg.MergeConfigMap(deepCopyMap(settings))
+	}
+}
+
+// fetchRemoteConfig downloads and parses a remote configuration payload.
+func fetchRemoteConfig(url string, opts HTTPLoaderOptions) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build remote config request: %w", err)
+	}
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch remote config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch remote config: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote config: %w", err)
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = formatFromContentType(resp.Header.Get("Content-Type"))
+	}
+
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewReader(body)); err != nil {
+		return nil, fmt.Errorf("failed to parse remote config: %w", err)
+	}
+
+	return v.AllSettings(), nil
+}
+
+// formatFromContentType maps a Content-Type header to a viper config type.
+func formatFromContentType(contentType string) string {
+	ct := strings.ToLower(contentType)
+	switch {
+	case strings.Contains(ct, "yaml"), strings.Contains(ct, "yml"):
+		return "yaml"
+	case strings.Contains(ct, "toml"):
+		return "toml"
+	default:
+		return "json"
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPLoaderMergesJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"server":{"port":9090}}`))
+	}))
+	defer srv.Close()
+
+	cfg, err := New(&Options{
+		Loaders: []Loader{HTTPLoader(srv.URL, HTTPLoaderOptions{
+			Headers: map[string]string{"X-Token": "secret"},
+		})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+}
+
+func TestHTTPLoaderYAMLFromContentType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte("app:\n  name: remote\n"))
+	}))
+	defer srv.Close()
+
+	cfg, err := New(&Options{Loaders: []Loader{HTTPLoader(srv.URL, HTTPLoaderOptions{})}})
+	require.NoError(t, err)
+	assert.Equal(t, "remote", cfg.GetString("app.name"))
+}
+
+func TestHTTPLoaderCacheTTL(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_, _ = w.Write([]byte(`{"a":1}`))
+	}))
+	defer srv.Close()
+
+	loader := HTTPLoader(srv.URL, HTTPLoaderOptions{CacheTTL: time.Minute})
+	cfg, err := New(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, loader(cfg))
+	require.NoError(t, loader(cfg))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+}
+
+func TestHTTPLoaderSharesConcurrentFetches(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		_, _ = w.Write([]byte(`{"db":{"pool":{"size":5}}}`))
+	}))
+	defer srv.Close()
+
+	loader := HTTPLoader(srv.URL, HTTPLoaderOptions{}) // No CacheTTL: only in-flight fetches are shared
+	configs := make([]*Config, 8)
+	for i := range configs {
+		cfg, err := New(nil)
+		require.NoError(t, err)
+		configs[i] = cfg
+	}
+
+	var wg sync.WaitGroup
+	for _, cfg := range configs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, loader(cfg))
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // Let every load join the fetch
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	for _, cfg := range configs {
+		assert.Equal(t, 5, cfg.GetInt("db.pool.size"))
+	}
+
+	// Each Config gets its own copy of the shared payload
+	configs[0].Set("db.pool.size", 10)
+	assert.Equal(t, 5, configs[1].GetInt("db.pool.size"))
+}
+
+func TestHTTPLoaderFailSoft(t *testing.T) {
+	fail := int32(0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"feature":{"enabled":true}}`))
+	}))
+	defer srv.Close()
+
+	loader := HTTPLoader(srv.URL, HTTPLoaderOptions{FailSoft: true})
+	cfg, err := New(nil)
+	require.NoError(t, err)
+	require.NoError(t, loader(cfg))
+
+	atomic.StoreInt32(&fail, 1)
+	require.NoError(t, loader(cfg))
+	assert.True(t, cfg.GetBool("feature.enabled"))
+
+	// Without cached data, the error surfaces
+	_, err = New(&Options{Loaders: []Loader{HTTPLoader(srv.URL, HTTPLoaderOptions{FailSoft: true})}})
+	assert.Error(t, err)
+}
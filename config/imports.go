@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// importsKey is the top-level key listing config files to merge beneath a file's own keys.
+//
+// Example config.yaml:
+//
+//	imports: [database.yaml, server.yaml]
+//	server:
+//	  port: 8080 # Wins over server.port from server.yaml
+const importsKey = "imports"
+
+// mergeFile merges the config file at path over the current values. Files listed
+// under its imports key are merged first, in order, so the file's own keys win.
+// chain holds the files importing path, to detect cycles. Caller must hold c.mu for writing.
+func (c *Config) mergeFile(path string, chain []string) error {
+	sub := viper.New()
+	sub.SetConfigFile(path)
+	if err := sub.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	settings := sub.AllSettings()
+	if err := c.mergeImports(settings, filepath.Dir(path), append(chain, absPath(path))); err != nil {
+		return err
+	}
+	delete(settings, importsKey)
+
+	// MergeConfigMap deep-merges nested maps: a partial overlay such as
+	// {database: {host: x}} keeps database.port from earlier files. Lists and
+	// scalars are replaced as a whole. TestNewEnvOverlayDeepMerges guards this,
+	// since viper's merge behavior has differed between versions.
+	if err := c.viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to merge config %s: %w", path, err)
+	}
+	c.recordFileKeyCase(path)
+	c.recordOrigin("", settings, OriginFile+":"+path)
+	return nil
+}
+
+// mergeImports merges the files listed under the imports key of settings, in order.
+// Relative paths are resolved against dir. chain ends with the importing file.
+// Caller must hold c.mu for writing.
+func (c *Config) mergeImports(settings map[string]interface{}, dir string, chain []string) error {
+	raw, ok := settings[importsKey]
+	if !ok {
+		return nil
+	}
+	imports, err := cast.ToStringSliceE(raw)
+	if err != nil {
+		return fmt.Errorf("config %s: %s must be a list of files: %w", chain[len(chain)-1], importsKey, err)
+	}
+
+	for _, name := range imports {
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = absPath(path)
+		if slices.Contains(chain, path) {
+			return fmt.Errorf("circular config import: %s", strings.Join(append(chain, path), " -> "))
+		}
+		if err := c.mergeFile(path, chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relayerBaseImports rebuilds viper so the files imported by the base config sit
+// beneath the base config's own keys. Called after the base config was read into
+// viper; a no-op if it has no imports key. Caller must hold c.mu for writing.
+func (c *Config) relayerBaseImports() error {
+	if !c.viper.InConfig(importsKey) {
+		return nil
+	}
+
+	// Start from an empty viper: its config layer already holds the base keys,
+	// which imported files must not override
+	used := c.viper.ConfigFileUsed()
+	c.viper = newViper(&c.opts)
+	c.origins = nil
+
+	if c.source == nil {
+		// Keep the base file as the one watched for changes
+		c.viper.SetConfigFile(used)
+		return c.mergeFile(used, nil)
+	}
+
+	sub := viper.New()
+	sub.SetConfigType(c.source.configType)
+	if err := sub.ReadConfig(bytes.NewReader(c.source.data)); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	settings := sub.AllSettings()
+	if err := c.mergeImports(settings, c.opts.searchPaths()[0], []string{memorySourceName}); err != nil {
+		return err
+	}
+	delete(settings, importsKey)
+	if err := c.viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to merge config: %w", err)
+	}
+	c.recordOrigin("", settings, OriginFile+":"+memorySourceName)
+	return nil
+}
+
+// absPath returns path made absolute, or path unchanged if that fails.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
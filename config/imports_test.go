@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResolvesImports(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "parts"), 0o755))
+	writeConfigFile(t, dir, "config.yaml", "imports: [parts/database.yaml, server.json]\nserver:\n  port: 8080\n")
+	writeConfigFile(t, dir, "parts/database.yaml", "imports: [pool.yaml]\ndatabase:\n  host: db.internal\n")
+	writeConfigFile(t, dir, "parts/pool.yaml", "database:\n  host: ignored\n  pool:\n    max: 20\n")
+	writeConfigFile(t, dir, "server.json", `{"server":{"host":"0.0.0.0","port":9000}}`)
+	writeConfigFile(t, dir, "config.production.yaml", "imports: [prod-server.yaml]\n")
+	writeConfigFile(t, dir, "prod-server.yaml", "server:\n  port: 80\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", cfg.GetString("database.host"))
+	assert.Equal(t, 20, cfg.GetInt("database.pool.max"))
+	assert.Equal(t, "0.0.0.0", cfg.GetString("server.host"))
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	assert.False(t, cfg.IsSet("imports"))
+	assert.Equal(t, OriginFile+":"+filepath.Join(dir, "server.json"), cfg.Origin("server.host"))
+	assert.Equal(t, filepath.Join(dir, "config.yaml"), cfg.Viper().ConfigFileUsed())
+
+	// Files imported by overlays sit beneath the overlay, above the base config
+	cfg, err = New(&Options{ConfigPath: dir, Env: Production})
+	require.NoError(t, err)
+	assert.Equal(t, 80, cfg.GetInt("server.port"))
+}
+
+func TestNewImportErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "imports: [a.yaml]\n")
+	writeConfigFile(t, dir, "a.yaml", "imports: [b.yaml]\n")
+	writeConfigFile(t, dir, "b.yaml", "imports: [a.yaml]\n")
+
+	_, err := New(&Options{ConfigPath: dir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "circular config import")
+
+	writeConfigFile(t, dir, "config.yaml", "imports: [missing.yaml]\n")
+	_, err = New(&Options{ConfigPath: dir})
+	assert.Error(t, err)
+}
+
+func TestNewFromBytesResolvesImports(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "database.yaml", "database:\n  host: db.internal\n  port: 5432\n")
+
+	cfg, err := NewFromBytes([]byte("imports: [database.yaml]\ndatabase:\n  port: 6432\n"), "yaml", &Options{ConfigPath: dir})
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", cfg.GetString("database.host"))
+	assert.Equal(t, 6432, cfg.GetInt("database.port"))
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Viper lowercases every key, so maps like featureFlags.NewUI come back as
+// featureflags.newui. Config records the original spelling of each key as it is
+// loaded (config files, MergeConfigMap, Set) so GetStringMapCaseSensitive can
+// restore it. Values still come from viper, so env overrides are honoured.
+
+// GetStringMapCaseSensitive returns a configuration value as map[string]interface{},
+// restoring the original key casing from the source that provided each key.
+// Keys whose original spelling is unknown (e.g. only set via environment) stay lowercase.
+// Original casing is tracked for YAML, JSON, and TOML config files.
+//
+// Example:
+//
+//	// config.yaml: featureFlags: {NewUI: true}
+//	cfg.GetStringMap("featureFlags")              // map[newui:true]
+//	cfg.GetStringMapCaseSensitive("featureFlags") // map[NewUI:true]
+func (c *Config) GetStringMapCaseSensitive(key string) map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.restoreKeyCase(strings.ToLower(key), c.viper.GetStringMap(key))
+}
+
+// restoreKeyCase copies m, renaming keys to their recorded original spelling.
+// Caller must hold c.mu.
+func (c *Config) restoreKeyCase(path string, m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		full := joinKeyPath(path, k)
+		name := k
+		if orig, ok := c.keyCase[full]; ok {
+			name = orig
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = c.restoreKeyCase(full, nested)
+		}
+		result[name] = v
+	}
+	return result
+}
+
+// recordKeyCase stores the original spelling of every key in m under path.
+// Caller must hold c.mu for writing.
+func (c *Config) recordKeyCase(path string, m map[string]interface{}) {
+	if c.keyCase == nil {
+		c.keyCase = make(map[string]string)
+	}
+	for k, v := range m {
+		full := joinKeyPath(path, strings.ToLower(k))
+		c.keyCase[full] = k
+		if nested, ok := v.(map[string]interface{}); ok {
+			c.recordKeyCase(full, nested)
+		}
+	}
+}
+
+// recordFileKeyCase decodes the config file at path without lowercasing keys and
+// records their spelling. Unsupported formats and read errors are ignored, since
+// casing is best-effort metadata. Caller must hold c.mu for writing.
+func (c *Config) recordFileKeyCase(path string) {
+	if path == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	c.recordDataKeyCase(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// recordDataKeyCase decodes config data of the given type (yaml, json, toml) without
+// lowercasing keys and records their spelling. Caller must hold c.mu for writing.
+func (c *Config) recordDataKeyCase(data []byte, configType string) {
+	var err error
+	raw := make(map[string]interface{})
+	switch strings.ToLower(configType) {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &raw)
+	case "json":
+		err = json.Unmarshal(data, &raw)
+	case "toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	c.recordKeyCase("", raw)
+}
+
+// joinKeyPath joins a parent path and key with viper's "." delimiter.
+func joinKeyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
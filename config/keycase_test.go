@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStringMapCaseSensitiveFromFile(t *testing.T) {
+	dir := t.TempDir()
+	content := "featureFlags:\n  NewUI: true\n  betaSearch:\n    MaxResults: 10\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600))
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"newui": true, "betasearch": map[string]interface{}{"maxresults": 10}},
+		cfg.GetStringMap("featureFlags"))
+	assert.Equal(t, map[string]interface{}{"NewUI": true, "betaSearch": map[string]interface{}{"MaxResults": 10}},
+		cfg.GetStringMapCaseSensitive("featureFlags"))
+}
+
+func TestGetStringMapCaseSensitiveFromSet(t *testing.T) {
+	cfg, err := New(nil)
+	require.NoError(t, err)
+
+	cfg.Set("limits", map[string]interface{}{"APIKeys": 5})
+	cfg.Set("limits.MaxUsers", 100)
+
+	assert.Equal(t, map[string]interface{}{"APIKeys": 5, "MaxUsers": 100}, cfg.GetStringMapCaseSensitive("limits"))
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a config file into dir for loading tests.
+func writeConfigFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func TestNewEnvConfigOverridesBase(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  host: localhost\n  port: 8080\n")
+	writeConfigFile(t, dir, "config.production.yaml", "server:\n  port: 80\n")
+
+	cfg, err := New(&Options{ConfigPath: dir, Env: "production"})
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.GetString("server.host"))
+	assert.Equal(t, 80, cfg.GetInt("server.port"))
+}
+
+func TestNewMergesMixedFormats(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  host: localhost\n  port: 8080\n")
+	writeConfigFile(t, dir, "config.production.toml", "[server]\nport = 80\n")
+	writeConfigFile(t, dir, "config.local.json", `{"server":{"host":"127.0.0.1"},"debug":true}`)
+
+	cfg, err := New(&Options{
+		ConfigPath:  dir,
+		Env:         "production",
+		ConfigNames: []string{"config.local", "config.missing"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", cfg.GetString("server.host"))
+	assert.Equal(t, 80, cfg.GetInt("server.port"))
+	assert.True(t, cfg.GetBool("debug"))
+}
+
+func TestNewMergesConfigPaths(t *testing.T) {
+	base, overrides := t.TempDir(), t.TempDir()
+	writeConfigFile(t, base, "config.yaml", "server:\n  host: localhost\n  port: 8080\ndebug: true\n")
+	writeConfigFile(t, base, "config.production.yaml", "server:\n  port: 80\n")
+	writeConfigFile(t, overrides, "config.json", `{"server":{"host":"app.internal","port":9000}}`)
+	writeConfigFile(t, overrides, "config.production.yaml", "debug: false\n")
+
+	cfg, err := New(&Options{
+		ConfigPaths: []string{base, overrides, filepath.Join(base, "missing")},
+		Env:         "production",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "app.internal", cfg.GetString("server.host"))
+	assert.Equal(t, 80, cfg.GetInt("server.port"))
+	assert.False(t, cfg.GetBool("debug"))
+	assert.Equal(t, OriginFile+":"+filepath.Join(overrides, "config.json"), cfg.Origin("server.host"))
+
+	// The base config may live only in a later directory
+	cfg, err = New(&Options{ConfigPaths: []string{t.TempDir(), overrides}})
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.GetInt("server.port"))
+}
+
+func TestNewAutoDetectsBaseType(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.toml", "[app]\nname = \"toml-app\"\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+	assert.Equal(t, "toml-app", cfg.GetString("app.name"))
+}
+
+func TestNewInvalidOverlayReturnsError(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.local.json", `{"broken":`)
+
+	_, err := New(&Options{ConfigPath: dir, ConfigNames: []string{"config.local"}})
+	assert.Error(t, err)
+}
+
+func TestNewEnvOverlayDeepMerges(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", `database:
+  host: localhost
+  port: 5432
+  pool:
+    max: 10
+    idle: 2
+  Options:
+    sslmode: disable
+    timezone: UTC
+`)
+	writeConfigFile(t, dir, "config.prod.yaml", `database:
+  host: db.prod.internal
+  pool:
+    max: 50
+  options:
+    sslmode: require
+`)
+
+	cfg, err := New(&Options{ConfigPath: dir, Env: "prod"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "db.prod.internal", cfg.GetString("database.host"))
+	assert.Equal(t, 5432, cfg.GetInt("database.port"))
+	assert.Equal(t, 50, cfg.GetInt("database.pool.max"))
+	assert.Equal(t, 2, cfg.GetInt("database.pool.idle"))
+	assert.Equal(t, "require", cfg.GetString("database.options.sslmode"))
+	assert.Equal(t, "UTC", cfg.GetString("database.options.timezone"))
+}
+
+func TestMergeConfigMapDeepMerges(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "database:\n  host: localhost\n  port: 5432\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+	require.NoError(t, cfg.MergeConfigMap(map[string]interface{}{
+		"database": map[string]interface{}{"host": "remote"},
+	}))
+
+	assert.Equal(t, "remote", cfg.GetString("database.host"))
+	assert.Equal(t, 5432, cfg.GetInt("database.port"))
+}
+
+func TestNewEnvOverlayReplacesLists(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "cors:\n  origins: [a.com, b.com]\n  maxAge: 600\n")
+	writeConfigFile(t, dir, "config.prod.yaml", "cors:\n  origins: [prod.com]\n")
+
+	cfg, err := New(&Options{ConfigPath: dir, Env: "prod"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"prod.com"}, cfg.GetStringSlice("cors.origins"))
+	assert.Equal(t, 600, cfg.GetInt("cors.maxage"))
+}
+
+func TestGetSliceAndMapSlice(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", `servers:
+  - host: a.internal
+    port: 80
+  - host: b.internal
+    port: 81
+  - not-a-map
+tags: [x, y]
+empty: []
+`)
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+
+	assert.Len(t, cfg.GetSlice("servers"), 3)
+	assert.Equal(t, []interface{}{"x", "y"}, cfg.GetSlice("tags"))
+
+	servers := cfg.GetMapSlice("servers")
+	require.Len(t, servers, 2)
+	assert.Equal(t, "a.internal", servers[0]["host"])
+	assert.Equal(t, 81, servers[1]["port"])
+
+	assert.Nil(t, cfg.GetSlice("empty"))
+	assert.Nil(t, cfg.GetSlice("missing"))
+	assert.Nil(t, cfg.GetMapSlice("tags"))
+	assert.Nil(t, cfg.GetMapSlice("missing"))
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// Origin tags returned by Config.Origin. File and secret origins are suffixed
+// with the path, env origins with the variable name.
+const (
+	OriginOverride = "override" // Set at runtime via Set
+	OriginEnv      = "env"      // Environment variable, e.g. "env:APP_SERVER_PORT"
+	OriginFile     = "file"     // Config file, e.g. "file:config/config.production.yaml"
+	OriginSecret   = "secret"   // Secret file, e.g. "secret:/run/secrets/db_password"
+	OriginMerge    = "merge"    // MergeConfigMap, including custom loaders
+	OriginMixed    = "mixed"    // Map key whose nested keys come from different sources
+)
+
+// Origin reports which source provided the current value of key, for debugging
+// layered configuration. Sources are checked in precedence order: Set overrides,
+// environment variables (including BindEnv bindings), then the last file, secret,
+// or MergeConfigMap layer that set the key. Returns "" if the key is not set.
+//
+// For a map key (e.g. "database"), the common origin of its nested keys is
+// returned, or OriginMixed if they differ.
+//
+// Example:
+//
+//	cfg.Origin("server.port")       // "file:config/config.production.yaml"
+//	cfg.Origin("database.password") // "env:APP_DATABASE_PASSWORD"
+func (c *Config) Origin(key string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	k := strings.ToLower(key)
+
+	// Runtime overrides win over everything
+	for i := len(c.overrides) - 1; i >= 0; i-- {
+		ok := strings.ToLower(c.overrides[i].key)
+		if ok == k || strings.HasPrefix(k, ok+".") {
+			return OriginOverride
+		}
+	}
+
+	// Environment variables (AutomaticEnv ignores empty values); nested keys
+	// only map to a variable name when LookupsEnv is on
+	if *c.opts.AutoEnvEnabled && (*c.opts.LookupsEnv || !strings.Contains(k, ".")) {
+		name := c.envKey(k)
+		if v, ok := os.LookupEnv(name); ok && v != "" {
+			return OriginEnv + ":" + name
+		}
+	}
+	if name, _, ok := c.boundEnv(k); ok {
+		return OriginEnv + ":" + name
+	}
+	for path := k; path != ""; path = parentKey(path) {
+		if origin, ok := c.envSlices[path]; ok {
+			return origin
+		}
+	}
+
+	// Exact key or the nearest parent recorded by a layer
+	for path := k; path != ""; path = parentKey(path) {
+		if origin, ok := c.origins[path]; ok {
+			return origin
+		}
+	}
+
+	// Map key: combine the origins of its nested keys
+	var origins []string
+	for path, origin := range c.origins {
+		if strings.HasPrefix(path, k+".") {
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) == 0 {
+		return ""
+	}
+	sort.Strings(origins)
+	if origins[0] != origins[len(origins)-1] {
+		return OriginMixed
+	}
+	return origins[0]
+}
+
+// recordOrigin tags every leaf key in settings with origin. Caller must hold c.mu for writing.
+func (c *Config) recordOrigin(path string, settings map[string]interface{}, origin string) {
+	if c.origins == nil {
+		c.origins = make(map[string]string)
+	}
+	for k, v := range settings {
+		full := joinKeyPath(path, strings.ToLower(k))
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			c.recordOrigin(full, nested, origin)
+			continue
+		}
+		// A leaf replaces anything previously recorded below it
+		for existing := range c.origins {
+			if strings.HasPrefix(existing, full+".") {
+				delete(c.origins, existing)
+			}
+		}
+		c.origins[full] = origin
+	}
+}
+
+// parentKey returns the parent of a dotted key path ("" for top-level keys).
+func parentKey(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
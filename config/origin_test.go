@@ -0,0 +1,32 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrigin(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  host: localhost\n  port: 8080\ndatabase:\n  host: db\n")
+	writeConfigFile(t, dir, "config.prod.yaml", "server:\n  port: 80\n")
+	t.Setenv("APP_DATABASE_HOST", "db.env")
+
+	cfg, err := New(&Options{ConfigPath: dir, Env: "prod", EnvPrefix: "APP"})
+	require.NoError(t, err)
+	require.NoError(t, cfg.MergeConfigMap(map[string]interface{}{"feature": map[string]interface{}{"beta": true}}))
+	cfg.Set("server.host", "0.0.0.0")
+
+	assert.Equal(t, "file:"+filepath.Join(dir, "config.prod.yaml"), cfg.Origin("server.port"))
+	assert.Equal(t, "override", cfg.Origin("server.host"))
+	assert.Equal(t, "env:APP_DATABASE_HOST", cfg.Origin("database.host"))
+	assert.Equal(t, "merge", cfg.Origin("feature.beta"))
+	assert.Equal(t, "mixed", cfg.Origin("server"))
+	assert.Equal(t, "", cfg.Origin("missing.key"))
+
+	require.NoError(t, cfg.Unset("server.host"))
+	assert.Equal(t, "file:"+filepath.Join(dir, "config.yaml"), cfg.Origin("server.host"))
+	assert.Equal(t, "merge", cfg.Origin("feature.beta"))
+}
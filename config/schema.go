@@ -0,0 +1,131 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// schemaResource is the in-memory URL the schema is registered under for compilation.
+const schemaResource = "config-schema.json"
+
+// SchemaViolation is a single JSON Schema violation.
+type SchemaViolation struct {
+	Key     string // Dotted config key path (empty for the root)
+	Message string // Human-readable description
+}
+
+// SchemaError is returned by ValidateSchema and lists every violation found.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+// Error formats all violations, one per line.
+func (e *SchemaError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		key := v.Key
+		if key == "" {
+			key = "(root)"
+		}
+		lines = append(lines, key+": "+v.Message)
+	}
+	return fmt.Sprintf("config schema validation failed with %d violation(s):\n%s",
+		len(e.Violations), strings.Join(lines, "\n"))
+}
+
+// ValidateSchema validates all settings against a JSON Schema document.
+// Keys keep their original casing from config files, so schema property names
+// should match the files. Returns *SchemaError listing every violation (not just
+// the first) with dotted key paths, or an error if the schema itself is invalid.
+//
+// Example:
+//
+//	schema, _ := os.ReadFile("config.schema.json")
+//	if err := cfg.ValidateSchema(schema); err != nil {
+//	    var schemaErr *config.SchemaError
+//	    if errors.As(err, &schemaErr) {
+//	        for _, v := range schemaErr.Violations {
+//	            log.Printf("%s: %s", v.Key, v.Message)
+//	        }
+//	    }
+//	    os.Exit(1)
+//	}
+func (c *Config) ValidateSchema(schemaJSON []byte) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return fmt.Errorf("failed to parse config schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaResource, doc); err != nil {
+		return fmt.Errorf("failed to load config schema: %w", err)
+	}
+	schema, err := compiler.Compile(schemaResource)
+	if err != nil {
+		return fmt.Errorf("failed to compile config schema: %w", err)
+	}
+
+	c.mu.RLock()
+	settings := c.restoreKeyCase("", c.viper.AllSettings())
+	c.mu.RUnlock()
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config settings: %w", err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode config settings: %w", err)
+	}
+
+	err = schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	violations := collectViolations(verr, message.NewPrinter(language.English), nil)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Key < violations[j].Key
+	})
+	return &SchemaError{Violations: violations}
+}
+
+// collectViolations flattens the leaf errors of a validation error tree.
+// Missing required properties are reported at the key of the missing property.
+func collectViolations(verr *jsonschema.ValidationError, p *message.Printer, out []SchemaViolation) []SchemaViolation {
+	if len(verr.Causes) > 0 {
+		for _, cause := range verr.Causes {
+			out = collectViolations(cause, p, out)
+		}
+		return out
+	}
+
+	key := strings.Join(verr.InstanceLocation, ".")
+	if req, ok := verr.ErrorKind.(*kind.Required); ok {
+		for _, missing := range req.Missing {
+			out = append(out, SchemaViolation{
+				Key:     joinKeyPath(key, missing),
+				Message: "missing required property",
+			})
+		}
+		return out
+	}
+
+	return append(out, SchemaViolation{
+		Key:     key,
+		Message: verr.ErrorKind.LocalizedString(p),
+	})
+}
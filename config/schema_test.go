@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["server", "appName"],
+	"properties": {
+		"appName": {"type": "string"},
+		"server": {
+			"type": "object",
+			"required": ["host", "port"],
+			"properties": {
+				"host": {"type": "string"},
+				"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+			}
+		}
+	}
+}`
+
+func TestValidateSchemaValid(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "appName: demo\nserver:\n  host: localhost\n  port: 8080\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+	assert.NoError(t, cfg.ValidateSchema([]byte(testSchema)))
+}
+
+func TestValidateSchemaReportsAllViolations(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: 70000\n")
+
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+
+	err = cfg.ValidateSchema([]byte(testSchema))
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+
+	keys := make([]string, 0, len(schemaErr.Violations))
+	for _, v := range schemaErr.Violations {
+		keys = append(keys, v.Key)
+	}
+	assert.Equal(t, []string{"appName", "server.host", "server.port"}, keys)
+	assert.Contains(t, err.Error(), "server.port")
+}
+
+func TestValidateSchemaInvalidSchema(t *testing.T) {
+	cfg, err := New(&Options{ConfigPath: t.TempDir()})
+	require.NoError(t, err)
+
+	err = cfg.ValidateSchema([]byte(`{"type": 12}`))
+	require.Error(t, err)
+	var schemaErr *SchemaError
+	assert.False(t, errors.As(err, &schemaErr))
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// secretFileSuffix marks environment variables that point at a file holding the value,
+// e.g. APP_DATABASE_PASSWORD_FILE=/run/secrets/db_password.
+const secretFileSuffix = "_FILE"
+
+// loadSecretFiles reads values from secret files (Docker/Kubernetes secrets) and merges
+// them over the loaded config files. Sources, in increasing priority:
+//   - {ENV_KEY}_FILE environment variables, e.g. APP_DATABASE_PASSWORD_FILE
+//   - Options.SecretFiles entries
+//
+// Trailing newlines are trimmed from file contents. A referenced file that cannot be
+// read is an error, since silently starting without a secret is worse than failing.
+func (c *Config) loadSecretFiles() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	paths := c.secretFileEnv()
+	for key, path := range c.opts.SecretFiles {
+		paths[key] = path
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	settings := make(map[string]interface{})
+	for key, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read secret file for %q: %w", key, err)
+		}
+		setNested(settings, strings.Split(key, "."), strings.TrimRight(string(data), "\r\n"))
+	}
+
+	if err := c.viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to merge secret files: %w", err)
+	}
+	c.recordKeyCase("", settings)
+	for key, path := range paths {
+		c.recordOrigin("", map[string]interface{}{strings.ToLower(key): nil}, OriginSecret+":"+path)
+	}
+	return nil
+}
+
+// secretFileEnv returns config keys referenced by *_FILE environment variables.
+// Only declared keys can be referenced: keys present in the loaded config, keys in
+// Options.SecretFiles, and Options.EnvOnlyKeys, e.g. database.password ->
+// APP_DATABASE_PASSWORD_FILE. A variable that is itself the name of an existing
+// key (APP_LOG_FILE for log.file) is an ordinary override, never a reference.
+// Caller must hold c.mu.
+func (c *Config) secretFileEnv() map[string]string {
+	overrides := make(map[string]bool)
+	declared := make(map[string]string)
+	for _, key := range c.viper.AllKeys() {
+		overrides[c.envKey(key)] = true
+		declared[c.envKey(key)+secretFileSuffix] = key
+	}
+	for key := range c.opts.SecretFiles {
+		declared[c.envKey(key)+secretFileSuffix] = strings.ToLower(key)
+	}
+	for _, key := range c.opts.EnvOnlyKeys {
+		declared[c.envKey(key)+secretFileSuffix] = strings.ToLower(key)
+	}
+
+	paths := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, path, ok := strings.Cut(kv, "=")
+		if !ok || path == "" || !strings.HasSuffix(name, secretFileSuffix) {
+			continue
+		}
+		name = strings.ToUpper(name)
+		if key, ok := declared[name]; ok && !overrides[name] {
+			paths[key] = path
+		}
+	}
+	return paths
+}
+
+// setNested stores value in m at the nested path, creating intermediate maps.
+func setNested(m map[string]interface{}, path []string, value interface{}) {
+	for _, p := range path[:len(path)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[p] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
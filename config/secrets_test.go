@@ -0,0 +1,63 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFilesOption(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "database:\n  host: localhost\n")
+	writeConfigFile(t, dir, "db_password", "s3cret\n")
+
+	cfg, err := New(&Options{
+		ConfigPath:  dir,
+		SecretFiles: map[string]string{"database.password": filepath.Join(dir, "db_password")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.GetString("database.password"))
+	assert.Equal(t, "localhost", cfg.GetString("database.host"))
+}
+
+func TestSecretFilesEnvConvention(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "database:\n  password: from-yaml\nsmtp:\n  password: \"\"\n")
+	writeConfigFile(t, dir, "db_password", "from-file\r\n")
+	writeConfigFile(t, dir, "smtp_password", "mail-pass")
+
+	t.Setenv("APP_DATABASE_PASSWORD_FILE", filepath.Join(dir, "db_password"))
+	t.Setenv("APP_SMTP_PASSWORD_FILE", filepath.Join(dir, "smtp_password"))
+
+	cfg, err := New(&Options{ConfigPath: dir, EnvPrefix: "APP"})
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.GetString("database.password"))
+	assert.Equal(t, "mail-pass", cfg.GetString("smtp.password"))
+}
+
+func TestSecretFilesEnvRequiresDeclaredKey(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "log:\n  level: info\n  file: /var/log/app.log\n")
+	writeConfigFile(t, dir, "api_key", "k3y")
+
+	t.Setenv("APP_LOG_FILE", "/tmp/nonexistent/app.log")      // Override of log.file, not a secret for log
+	t.Setenv("APP_UNKNOWN_TOKEN_FILE", "/nonexistent/secret") // Undeclared key
+	t.Setenv("APP_API_KEY_FILE", filepath.Join(dir, "api_key"))
+
+	cfg, err := New(&Options{ConfigPath: dir, EnvPrefix: "APP", EnvOnlyKeys: []string{"api.key"}})
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/nonexistent/app.log", cfg.GetString("log.file"))
+	assert.Equal(t, "info", cfg.GetString("log.level"))
+	assert.False(t, cfg.IsSet("unknown.token"))
+	assert.Equal(t, "k3y", cfg.GetString("api.key"))
+}
+
+func TestSecretFilesMissingFile(t *testing.T) {
+	_, err := New(&Options{
+		ConfigPath:  t.TempDir(),
+		SecretFiles: map[string]string{"api.key": "/nonexistent/secret"},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// memorySourceName identifies in-memory base config in Origin results, e.g. "file:<memory>".
+const memorySourceName = "<memory>"
+
+// dataSource is base config held in memory instead of read from ConfigPath.
+type dataSource struct {
+	data       []byte
+	configType string
+}
+
+// NewFromBytes creates a Config whose base config is parsed from data instead of
+// {ConfigPath}/{ConfigName}.{ext}. configType is the format of data (yaml, json,
+// toml, etc.). Everything else works as with New: environment variables, secret
+// files, loaders, and Set apply on top, and Env or ConfigNames overlays are still
+// merged from ConfigPath when set. data is copied, so the caller may reuse it.
+//
+// WatchConfig and WatchValidated have no file to watch for in-memory config.
+//
+// Example:
+//
+//	//go:embed config.yaml
+//	var defaultConfig []byte
+//
+//	cfg, err := config.NewFromBytes(defaultConfig, "yaml", &config.Options{
+//	    EnvPrefix: "APP",
+//	})
+//	if err != nil {
+//	    panic(err)
+//	}
+func NewFromBytes(data []byte, configType string, opts *Options) (*Config, error) {
+	if configType == "" {
+		return nil, errors.New("config type is required for in-memory config")
+	}
+	return newConfig(opts, &dataSource{data: bytes.Clone(data), configType: configType})
+}
+
+// NewFromReader creates a Config whose base config is read from r. It reads r to
+// the end and otherwise behaves like NewFromBytes.
+//
+// Example:
+//
+//	f, _ := embedded.Open("config/config.yaml")
+//	defer f.Close()
+//	cfg, err := config.NewFromReader(f, "yaml", nil)
+func NewFromReader(r io.Reader, configType string, opts *Options) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return NewFromBytes(data, configType, opts)
+}
+
+// loadSource parses the in-memory base config into viper. Caller must hold c.mu for writing.
+func (c *Config) loadSource() error {
+	c.viper.SetConfigType(c.source.configType)
+	if err := c.viper.ReadConfig(bytes.NewReader(c.source.data)); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	c.recordDataKeyCase(c.source.data, c.source.configType)
+	c.recordOrigin("", c.viper.AllSettings(), OriginFile+":"+memorySourceName)
+	return c.relayerBaseImports()
+}
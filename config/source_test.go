@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromBytes(t *testing.T) {
+	t.Setenv("APP_SERVER_PORT", "9090")
+
+	data := []byte("server:\n  host: localhost\n  port: 8080\nfeatureFlags:\n  NewUI: true\n")
+	cfg, err := NewFromBytes(data, "yaml", &Options{EnvPrefix: "APP"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost", cfg.GetString("server.host"))
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	assert.Equal(t, map[string]interface{}{"NewUI": true}, cfg.GetStringMapCaseSensitive("featureFlags"))
+	assert.Equal(t, "file:<memory>", cfg.Origin("server.host"))
+
+	// Unset rebuilds from the in-memory source
+	cfg.Set("server.host", "example.com")
+	require.NoError(t, cfg.Unset("server.host"))
+	assert.Equal(t, "localhost", cfg.GetString("server.host"))
+}
+
+func TestNewFromReader(t *testing.T) {
+	cfg, err := NewFromReader(strings.NewReader(`{"debug":true}`), "json", nil)
+	require.NoError(t, err)
+	assert.True(t, cfg.GetBool("debug"))
+}
+
+func TestNewFromBytesErrors(t *testing.T) {
+	_, err := NewFromBytes([]byte("debug: true"), "", nil)
+	assert.Error(t, err)
+
+	_, err = NewFromBytes([]byte("server: [unclosed"), "yaml", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// ErrKeyNotFound is returned by the GetXxxE accessors when a key is not set.
+var ErrKeyNotFound = errors.New("config key not found")
+
+// GetIntE returns a configuration value as int. Unlike GetInt, it returns an error
+// wrapping ErrKeyNotFound if the key is not set, and a descriptive error if the
+// value can't be converted (e.g. "abc"), instead of silently returning 0.
+//
+// Example:
+//
+//	port, err := cfg.GetIntE("server.port")
+//	if errors.Is(err, config.ErrKeyNotFound) {
+//	    port = 8080
+//	} else if err != nil {
+//	    log.Fatal(err) // server.port is set but malformed
+//	}
+func (c *Config) GetIntE(key string) (int, error) {
+	return getE(c, key, "int", cast.ToIntE)
+}
+
+// GetBoolE returns a configuration value as bool, or an error if the key is not set
+// (wrapping ErrKeyNotFound) or the value can't be converted. See GetIntE.
+func (c *Config) GetBoolE(key string) (bool, error) {
+	return getE(c, key, "bool", cast.ToBoolE)
+}
+
+// GetFloat64E returns a configuration value as float64, or an error if the key is
+// not set (wrapping ErrKeyNotFound) or the value can't be converted. See GetIntE.
+func (c *Config) GetFloat64E(key string) (float64, error) {
+	return getE(c, key, "float64", cast.ToFloat64E)
+}
+
+// GetDurationE returns a configuration value as time.Duration, or an error if the
+// key is not set (wrapping ErrKeyNotFound) or the value can't be converted.
+// Strings use time.ParseDuration syntax ("30s"); bare numbers are nanoseconds.
+func (c *Config) GetDurationE(key string) (time.Duration, error) {
+	return getE(c, key, "duration", cast.ToDurationE)
+}
+
+// GetStringMapDurationE returns a configuration value as map[string]time.Duration, or an
+// error if the key is not set (wrapping ErrKeyNotFound) or any entry can't be converted.
+// The error names every malformed entry.
+//
+// Example:
+//
+//	timeouts, err := cfg.GetStringMapDurationE("timeouts")
+//	if err != nil {
+//	    log.Fatal(err) // e.g. config key timeouts: invalid duration values: write="10 secs"
+//	}
+func (c *Config) GetStringMapDurationE(key string) (map[string]time.Duration, error) {
+	if c.Get(key) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+
+	result := make(map[string]time.Duration)
+	var invalid []string
+	for k, v := range c.GetStringMap(key) {
+		d, err := cast.ToDurationE(v)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s=%q", k, fmt.Sprint(v)))
+			continue
+		}
+		result[k] = d
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, fmt.Errorf("config key %s: invalid duration values: %s", key, strings.Join(invalid, ", "))
+	}
+	return result, nil
+}
+
+// GetIntSliceE returns a configuration value as []int, accepting the same forms as
+// GetIntSlice (lists, JSON array strings, and delimited strings), or an error if the
+// key is not set (wrapping ErrKeyNotFound) or any element isn't a valid integer.
+//
+// Example:
+//
+//	// APP_PORTS=8080,80a1
+//	ports, err := cfg.GetIntSliceE("ports")
+//	// err: config key ports: invalid int elements: "80a1"
+func (c *Config) GetIntSliceE(key string) ([]int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.viper.Get(key) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+
+	ints, invalid := c.intSlice(key)
+	if len(invalid) > 0 {
+		quoted := make([]string, len(invalid))
+		for i, v := range invalid {
+			quoted[i] = strconv.Quote(v)
+		}
+		return nil, fmt.Errorf("config key %s: invalid int elements: %s", key, strings.Join(quoted, ", "))
+	}
+	return ints, nil
+}
+
+// GetEnum returns a configuration value that must be one of allowed, matched
+// case-insensitively and returned in its canonical spelling from allowed
+// ("JSON" -> "json"). If the key is not set or empty, def is returned with a nil
+// error. If the value isn't allowed, def is returned along with an error listing
+// the allowed values, so callers can either fail fast or fall back.
+//
+// Example:
+//
+//	format, err := cfg.GetEnum("log.format", []string{"json", "console"}, "json")
+//	if err != nil {
+//	    log.Fatal(err) // e.g. config key log.format: invalid value "jsno", must be one of: json, console
+//	}
+//	sslMode, _ := cfg.GetEnum("db.sslmode", []string{"disable", "require", "verify-full"}, "require")
+func (c *Config) GetEnum(key string, allowed []string, def string) (string, error) {
+	val := strings.TrimSpace(c.GetString(key))
+	if val == "" {
+		return def, nil
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(val, a) {
+			return a, nil
+		}
+	}
+	return def, fmt.Errorf("config key %s: invalid value %q, must be one of: %s", key, val, strings.Join(allowed, ", "))
+}
+
+// getE reads key and converts it with conv, reporting missing and malformed values.
+func getE[T any](c *Config, key, typeName string, conv func(interface{}) (T, error)) (T, error) {
+	var zero T
+	val := c.Get(key)
+	if val == nil {
+		return zero, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	v, err := conv(val)
+	if err != nil {
+		return zero, fmt.Errorf("config key %s: invalid %s value %q: %w", key, typeName, fmt.Sprint(val), err)
+	}
+	return v, nil
+}
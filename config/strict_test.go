@@ -0,0 +1,134 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEAccessors(t *testing.T) {
+	cfg, err := New(&Options{ConfigPath: t.TempDir()})
+	require.NoError(t, err)
+
+	cfg.Set("server.port", "8080")
+	cfg.Set("debug", "true")
+	cfg.Set("ratio", 0.5)
+	cfg.Set("timeout", "30s")
+
+	port, err := cfg.GetIntE("server.port")
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	debug, err := cfg.GetBoolE("debug")
+	require.NoError(t, err)
+	assert.True(t, debug)
+
+	ratio, err := cfg.GetFloat64E("ratio")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, ratio)
+
+	timeout, err := cfg.GetDurationE("timeout")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, timeout)
+}
+
+func TestGetEAccessorsMissingKey(t *testing.T) {
+	cfg, err := New(&Options{ConfigPath: t.TempDir()})
+	require.NoError(t, err)
+
+	_, err = cfg.GetIntE("server.port")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = cfg.GetDurationE("timeout")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestGetEAccessorsMalformedValue(t *testing.T) {
+	cfg, err := New(&Options{ConfigPath: t.TempDir()})
+	require.NoError(t, err)
+
+	cfg.Set("server.port", "abc")
+	cfg.Set("debug", "maybe")
+	cfg.Set("ratio", "half")
+	cfg.Set("timeout", "soon")
+
+	_, err = cfg.GetIntE("server.port")
+	assert.ErrorContains(t, err, `config key server.port: invalid int value "abc"`)
+	assert.NotErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = cfg.GetBoolE("debug")
+	assert.Error(t, err)
+	_, err = cfg.GetFloat64E("ratio")
+	assert.Error(t, err)
+	_, err = cfg.GetDurationE("timeout")
+	assert.Error(t, err)
+
+	assert.Equal(t, 0, cfg.GetInt("server.port"), "GetInt keeps its lenient behavior")
+}
+
+func TestGetEAccessorsFromEnv(t *testing.T) {
+	t.Setenv("APP_WORKERS", "four")
+	cfg, err := New(&Options{ConfigPath: t.TempDir(), EnvPrefix: "APP"})
+	require.NoError(t, err)
+
+	_, err = cfg.GetIntE("workers")
+	assert.ErrorContains(t, err, "invalid int value")
+}
+
+func TestGetStringMapDurationE(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "timeouts:\n  read: 5s\n  write: 10s\nbad:\n  read: 5s\n  write: 10 secs\n")
+	cfg, err := New(&Options{ConfigPath: dir})
+	require.NoError(t, err)
+
+	timeouts, err := cfg.GetStringMapDurationE("timeouts")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second}, timeouts)
+
+	_, err = cfg.GetStringMapDurationE("bad")
+	assert.ErrorContains(t, err, `config key bad: invalid duration values: write="10 secs"`)
+
+	_, err = cfg.GetStringMapDurationE("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestGetIntSliceE(t *testing.T) {
+	t.Setenv("APP_PORTS", "8080,80a1,x")
+	t.Setenv("APP_ADMIN_PORTS", "[9090,9091]")
+
+	cfg, err := New(&Options{EnvPrefix: "APP"})
+	require.NoError(t, err)
+
+	_, err = cfg.GetIntSliceE("ports")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid int elements: "80a1", "x"`)
+
+	ports, err := cfg.GetIntSliceE("admin.ports")
+	require.NoError(t, err)
+	assert.Equal(t, []int{9090, 9091}, ports)
+
+	_, err = cfg.GetIntSliceE("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestGetEnum(t *testing.T) {
+	t.Setenv("APP_LOG_FORMAT", "JSON")
+	t.Setenv("APP_DB_SSLMODE", "verify_full")
+
+	cfg, err := New(&Options{EnvPrefix: "APP"})
+	require.NoError(t, err)
+	modes := []string{"disable", "require", "verify-full"}
+
+	format, err := cfg.GetEnum("log.format", []string{"json", "console"}, "console")
+	require.NoError(t, err)
+	assert.Equal(t, "json", format)
+
+	mode, err := cfg.GetEnum("db.sslmode", modes, "require")
+	assert.EqualError(t, err, `config key db.sslmode: invalid value "verify_full", must be one of: disable, require, verify-full`)
+	assert.Equal(t, "require", mode)
+
+	mode, err = cfg.GetEnum("missing", modes, "disable")
+	require.NoError(t, err)
+	assert.Equal(t, "disable", mode)
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// maxReloadAttempts bounds how often a validated reload is retried when runtime
+// changes (Set, MergeConfigMap, Unset) race with it.
+const maxReloadAttempts = 3
+
+// WatchValidated watches the config file and applies changes only if they pass
+// validation. On each change the files and loaders are loaded into a candidate Config
+// (with MergeConfigMap layers and Set overrides replayed), validate is called with the
+// candidate, and only if it returns nil is the candidate swapped in and onApply called.
+// Otherwise the previous configuration stays in effect and the error is passed to
+// Options.OnReloadError. Unparseable files are rejected the same way.
+//
+// validate must only read from the candidate it receives; the live Config still
+// holds the old values while it runs. Use WatchValidated instead of WatchConfig,
+// which applies changes without validation: a Config has at most one watcher, so
+// calling WatchValidated again, or after WatchConfig, has no effect.
+//
+// Only the base config file is watched. Edits to imported files or environment
+// variables are picked up by the next reload, but don't trigger one.
+//
+// Example:
+//
+//	cfg, _ := config.New(&config.Options{
+//	    ConfigPath: "./config",
+//	    OnReloadError: func(err error) {
+//	        logger.Error("config reload rejected", zap.Error(err))
+//	    },
+//	})
+//	cfg.WatchValidated(func(next *config.Config) error {
+//	    if next.GetInt("server.port") <= 0 {
+//	        return errors.New("server.port must be positive")
+//	    }
+//	    return nil
+//	}, func() {
+//	    logger.Info("config reloaded")
+//	})
+func (c *Config) WatchValidated(validate func(*Config) error, onApply func()) {
+	if !c.startWatching() {
+		return
+	}
+
+	c.watchFile(func() {
+		if err := c.reload(validate); err != nil {
+			c.reportReloadError(err)
+			return
+		}
+		if onApply != nil {
+			onApply()
+		}
+	})
+}
+
+// startWatching marks the Config as watched and reports whether it wasn't already,
+// so WatchConfig and WatchValidated never start more than one watcher.
+func (c *Config) startWatching() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.watching {
+		return false
+	}
+	c.watching = true
+	return true
+}
+
+// watchFile calls onChange whenever the base config file changes; imported files
+// are not watched. If no config file was loaded, the error is passed to Options.OnReloadError instead.
+func (c *Config) watchFile(onChange func()) {
+	c.mu.RLock()
+	file := c.viper.ConfigFileUsed()
+	c.mu.RUnlock()
+
+	if file == "" {
+		c.reportReloadError(errors.New("no config file loaded to watch"))
+		return
+	}
+
+	// A separate viper only triggers reloads, so the live one is never modified in place
+	trigger := viper.New()
+	trigger.SetConfigFile(file)
+	trigger.OnConfigChange(func(fsnotify.Event) { onChange() })
+	trigger.WatchConfig()
+}
+
+// reload builds a candidate Config, validates it if validate is non-nil, and swaps it in.
+// If runtime changes land while loading, the candidate is rebuilt so they aren't lost.
+func (c *Config) reload(validate func(*Config) error) error {
+	for attempt := 0; attempt < maxReloadAttempts; attempt++ {
+		c.mu.RLock()
+		revision := c.revision.Load()
+		next, err := c.build(true)
+		c.mu.RUnlock()
+		if err != nil {
+			return fmt.Errorf("config reload failed: %w", err)
+		}
+
+		if validate != nil {
+			if err := validate(next); err != nil {
+				return fmt.Errorf("config reload rejected: %w", err)
+			}
+		}
+
+		c.mu.Lock()
+		if c.revision.Load() == revision {
+			c.swap(next)
+			c.mu.Unlock()
+			return nil
+		}
+		c.mu.Unlock()
+	}
+	return errors.New("config reload abandoned: configuration changed concurrently")
+}
+
+// reportReloadError passes err to Options.OnReloadError, if set.
+func (c *Config) reportReloadError(err error) {
+	if c.opts.OnReloadError != nil {
+		c.opts.OnReloadError(err)
+	}
+}
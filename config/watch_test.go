@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchValidatedRejectsInvalidChange(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: 8080\n")
+
+	rejected := make(chan error, 16)
+	cfg, err := New(&Options{
+		ConfigPath:    dir,
+		OnReloadError: func(err error) { rejected <- err },
+	})
+	require.NoError(t, err)
+	cfg.Set("feature", true)
+
+	applied := make(chan struct{}, 16)
+	cfg.WatchValidated(func(next *Config) error {
+		if next.GetInt("server.port") <= 0 {
+			return errors.New("server.port must be positive")
+		}
+		return nil
+	}, func() { applied <- struct{}{} })
+
+	// A Config has a single watcher: neither of these may reload it
+	var extra atomic.Bool
+	cfg.Watch(func() { extra.Store(true) })
+	cfg.WatchConfig()
+	cfg.WatchValidated(func(*Config) error {
+		extra.Store(true)
+		return nil
+	}, nil)
+
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: -1\n")
+	select {
+	case err := <-rejected:
+		assert.ErrorContains(t, err, "server.port must be positive")
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected invalid change to be rejected")
+	}
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+	// Unset rebuilds from the last accepted file layer, not the rejected file on disk
+	cfg.Set("runtime.maintenance", true)
+	require.NoError(t, cfg.Unset("runtime.maintenance"))
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	assert.Equal(t, "file:"+filepath.Join(dir, "config.yaml"), cfg.Origin("server.port"))
+
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: 9090\n")
+	select {
+	case <-applied:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected valid change to be applied")
+	}
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	assert.True(t, cfg.GetBool("feature"), "runtime overrides should survive the reload")
+	assert.False(t, extra.Load(), "only the first watcher should reload")
+}
+
+func TestWatchConfigReloadsFullConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  host: a.internal\n  port: 8080\n")
+	writeConfigFile(t, dir, "config.production.yaml", "server:\n  port: 80\n")
+
+	var loads int32
+	cfg, err := New(&Options{
+		ConfigPath: dir,
+		Env:        Production,
+		Loaders: []Loader{func(cfg *Config) error {
+			n := atomic.AddInt32(&loads, 1)
+			cfg.Set("remote.version", int(n))
+			return cfg.MergeConfigMap(map[string]interface{}{"remote": map[string]interface{}{"region": "us"}})
+		}},
+	})
+	require.NoError(t, err)
+	cfg.Set("feature", true)
+
+	// Unset replays the loader layers instead of running the loaders again
+	cfg.Set("tmp", 1)
+	require.NoError(t, cfg.Unset("tmp"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+	assert.Equal(t, 1, cfg.GetInt("remote.version"))
+
+	reloaded := make(chan struct{}, 16)
+	cfg.Watch(func() { reloaded <- struct{}{} })
+	cfg.WatchConfig()
+	cfg.WatchConfig() // No second watcher
+
+	writeConfigFile(t, dir, "config.yaml", "server:\n  host: b.internal\n  port: 8080\n")
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected change to be applied")
+	}
+
+	assert.Equal(t, "b.internal", cfg.GetString("server.host"))
+	assert.Equal(t, 80, cfg.GetInt("server.port"), "overlays should be reloaded")
+	assert.Equal(t, int(atomic.LoadInt32(&loads)), cfg.GetInt("remote.version"), "loaders should run again")
+	assert.Equal(t, "us", cfg.GetString("remote.region"))
+	assert.True(t, cfg.GetBool("feature"), "runtime overrides should survive the reload")
+}
+
+func TestWatchValidatedRejectsUnparseableFile(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: 8080\n")
+
+	rejected := make(chan error, 16)
+	cfg, err := New(&Options{
+		ConfigPath:    dir,
+		OnReloadError: func(err error) { rejected <- err },
+	})
+	require.NoError(t, err)
+	cfg.WatchValidated(nil, nil)
+
+	writeConfigFile(t, dir, "config.yaml", "server: [port: 8080\n")
+	select {
+	case err := <-rejected:
+		assert.ErrorContains(t, err, "config reload failed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected unparseable change to be rejected")
+	}
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+}
+
+func TestWatchValidatedWithoutConfigFile(t *testing.T) {
+	var got error
+	cfg, err := New(&Options{
+		ConfigPath:    filepath.Join(t.TempDir(), "missing"),
+		OnReloadError: func(err error) { got = err },
+	})
+	require.NoError(t, err)
+
+	cfg.WatchValidated(nil, nil)
+	assert.ErrorContains(t, got, "no config file")
+}
@@ -9,6 +9,8 @@ type tenantKey struct{}
 type applicationKey struct{}
 type apiKeyPrefixKey struct{}
 type tenantAppValuesKey struct{}
+type requestIDKey struct{}
+type userKey struct{}
 
 // TenantAuthValues holds authentication context values for multi-tenant applications.
 type TenantAuthValues struct {
@@ -67,6 +69,42 @@ func APIKeyActor(ctx context.Context) (string, bool) {
 	return s, ok
 }
 
+// WithUser stores an authenticated user ID in context.
+func WithUser(ctx context.Context, userID string) context.Context {
+	if userID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserID extracts the user ID from context if present.
+func UserID(ctx context.Context) (string, bool) {
+	v := ctx.Value(userKey{})
+	if v == nil {
+		return "", false
+	}
+	id, ok := v.(string)
+	return id, ok
+}
+
+// WithRequestID stores a request ID in context for correlation across layers.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID extracts the request ID from context if present.
+func RequestID(ctx context.Context) (string, bool) {
+	v := ctx.Value(requestIDKey{})
+	if v == nil {
+		return "", false
+	}
+	id, ok := v.(string)
+	return id, ok
+}
+
 // WithTenantAuthValues stores combined tenant and application auth values in context.
 func WithTenantAuthValues(ctx context.Context, values TenantAuthValues) context.Context {
 	return context.WithValue(ctx, tenantAppValuesKey{}, values)
@@ -74,12 +112,15 @@ func WithTenantAuthValues(ctx context.Context, values TenantAuthValues) context.
 
 // TenantAuth extracts combined tenant and application auth values from context.
 // Falls back to individual extraction if combined values not found.
+// A tenant is required: it returns false when only an application is present
+// (e.g. platform-level API keys), even via WithTenantAuthValues. Use AppAuth for
+// checks that accept app-scoped callers without a tenant.
 func TenantAuth(ctx context.Context) (TenantAuthValues, bool) {
 	var result TenantAuthValues
 
 	// Check for combined values first
 	tenantAppValues, ok := ctx.Value(tenantAppValuesKey{}).(TenantAuthValues)
-	if ok {
+	if ok && tenantAppValues.TenantID != "" {
 		return tenantAppValues, true
 	}
 
@@ -94,3 +135,73 @@ func TenantAuth(ctx context.Context) (TenantAuthValues, bool) {
 
 	return result, true
 }
+
+// AppAuth extracts auth values for an application, with or without a tenant.
+// It succeeds when an app ID is present, from WithTenantAuthValues or WithApplication;
+// TenantID is filled in when available. Use it for platform-scoped checks and
+// TenantAuth for tenant-scoped ones.
+//
+// Example:
+//
+//	auth, ok := contextx.AppAuth(ctx)
+//	if !ok {
+//	    return util.UnauthorizedError("application credentials required")
+//	}
+//	if auth.TenantID == "" {
+//	    // Platform-level key: not bound to a tenant
+//	}
+func AppAuth(ctx context.Context) (TenantAuthValues, bool) {
+	// Check for combined values first
+	tenantAppValues, ok := ctx.Value(tenantAppValuesKey{}).(TenantAuthValues)
+	if ok && tenantAppValues.AppID != "" {
+		return tenantAppValues, true
+	}
+
+	// Fallback to individual extraction
+	appID, ok := AppID(ctx)
+	if !ok {
+		return TenantAuthValues{}, false
+	}
+	tenantID, _ := TenantID(ctx)
+
+	return TenantAuthValues{TenantID: tenantID, AppID: appID}, true
+}
+
+// WithTenantDeadline stores a tenant ID and derives a context that times out after d.
+// The returned CancelFunc must be called to release resources.
+func WithTenantDeadline(ctx context.Context, tenantID string, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(WithTenant(ctx, tenantID), d)
+}
+
+// Must returns the tenant auth values from context and panics if absent.
+// Use only in code paths where tenant presence is guaranteed by middleware.
+func Must(ctx context.Context) TenantAuthValues {
+	values, ok := TenantAuth(ctx)
+	if !ok {
+		panic("contextx: tenant auth values not found in context")
+	}
+	return values
+}
+
+// Fields returns the identity values present in context as a flat map,
+// suitable for enriching logs. Keys: "tenant", "app", "user".
+// Absent values are omitted.
+func Fields(ctx context.Context) map[string]string {
+	fields := make(map[string]string, 3)
+
+	if auth, ok := TenantAuth(ctx); ok {
+		if auth.TenantID != "" {
+			fields["tenant"] = auth.TenantID
+		}
+		if auth.AppID != "" {
+			fields["app"] = auth.AppID
+		}
+	} else if auth, ok := AppAuth(ctx); ok {
+		fields["app"] = auth.AppID
+	}
+	if userID, ok := UserID(ctx); ok {
+		fields["user"] = userID
+	}
+
+	return fields
+}
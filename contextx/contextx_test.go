@@ -127,3 +127,103 @@ func TestTenantAuthNoTenant(t *testing.T) {
 		t.Fatal("expected tenant auth to fail when no tenant ID")
 	}
 }
+
+func TestTenantAuthRequiresTenant(t *testing.T) {
+	ctx := WithApplication(context.Background(), "app-101")
+	if _, ok := TenantAuth(ctx); ok {
+		t.Fatal("expected tenant auth to fail with only an app ID")
+	}
+
+	ctx = WithTenantAuthValues(context.Background(), TenantAuthValues{AppID: "app-101", Prefix: "pk_"})
+	if _, ok := TenantAuth(ctx); ok {
+		t.Fatal("expected tenant auth to fail for combined values without a tenant")
+	}
+}
+
+func TestAppAuth(t *testing.T) {
+	// Platform-level key: app without tenant
+	ctx := WithApplication(context.Background(), "app-101")
+	auth, ok := AppAuth(ctx)
+	if !ok || auth.AppID != "app-101" || auth.TenantID != "" {
+		t.Fatalf("expected app-only auth, got %+v (ok=%v)", auth, ok)
+	}
+
+	// Tenant is included when present
+	auth, ok = AppAuth(WithTenant(ctx, "tenant-789"))
+	if !ok || auth.TenantID != "tenant-789" {
+		t.Fatalf("expected tenant to be filled in, got %+v", auth)
+	}
+
+	// Combined values
+	ctx = WithTenantAuthValues(context.Background(), TenantAuthValues{AppID: "app-202", Prefix: "pk_"})
+	auth, ok = AppAuth(ctx)
+	if !ok || auth.AppID != "app-202" || auth.Prefix != "pk_" {
+		t.Fatalf("expected combined app auth, got %+v (ok=%v)", auth, ok)
+	}
+
+	// Tenant without app
+	if _, ok := AppAuth(WithTenant(context.Background(), "tenant-789")); ok {
+		t.Fatal("expected app auth to fail without an app ID")
+	}
+}
+
+func TestWithRequestIDAndRequestID(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "rid-123")
+
+	extracted, ok := RequestID(ctx)
+	if !ok {
+		t.Fatal("expected request ID to be present")
+	}
+	if extracted != "rid-123" {
+		t.Fatalf("expected rid-123, got %s", extracted)
+	}
+}
+
+func TestWithRequestIDEmptyString(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "")
+
+	if _, ok := RequestID(ctx); ok {
+		t.Fatal("expected empty request ID to not be stored")
+	}
+}
+
+func TestWithTenantDeadline(t *testing.T) {
+	ctx, cancel := WithTenantDeadline(context.Background(), "tenant-123", time.Minute)
+	defer cancel()
+
+	if id, ok := TenantID(ctx); !ok || id != "tenant-123" {
+		t.Fatalf("expected tenant-123, got %q", id)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("expected deadline to be set")
+	}
+}
+
+func TestMust(t *testing.T) {
+	ctx := WithTenant(context.Background(), "tenant-123")
+	if got := Must(ctx); got.TenantID != "tenant-123" {
+		t.Fatalf("expected tenant-123, got %s", got.TenantID)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Must to panic without tenant")
+		}
+	}()
+	Must(context.Background())
+}
+
+func TestFields(t *testing.T) {
+	ctx := WithTenant(context.Background(), "tenant-123")
+	ctx = WithApplication(ctx, "app-456")
+	ctx = WithUser(ctx, "user-789")
+
+	fields := Fields(ctx)
+	if fields["tenant"] != "tenant-123" || fields["app"] != "app-456" || fields["user"] != "user-789" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+
+	if len(Fields(context.Background())) != 0 {
+		t.Fatal("expected no fields for empty context")
+	}
+}
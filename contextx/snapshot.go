@@ -0,0 +1,93 @@
+package contextx
+
+import (
+	"context"
+	"time"
+)
+
+// AuthSnapshot is a plain, serializable copy of the identity values in a context.
+// Capture it with Snapshot when handing work to another goroutine or process
+// (e.g. a queue message), and rehydrate it with Restore on the worker side.
+// Absent values are left empty and omitted from JSON.
+type AuthSnapshot struct {
+	RequestID string     `json:"request_id,omitempty"`
+	TenantID  string     `json:"tenant_id,omitempty"`
+	AppID     string     `json:"app_id,omitempty"`
+	UserID    string     `json:"user_id,omitempty"`
+	Prefix    string     `json:"api_key_prefix,omitempty"` // API key prefix (actor)
+	LastUsed  *time.Time `json:"last_used,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Snapshot captures all identity values present in ctx. Times are copied, so the
+// snapshot doesn't share memory with values stored in the context.
+//
+// Example:
+//
+//	job := Job{Auth: contextx.Snapshot(ctx), Payload: payload}
+//	data, _ := json.Marshal(job)
+//	queue.Publish(data)
+func Snapshot(ctx context.Context) AuthSnapshot {
+	var snap AuthSnapshot
+
+	snap.RequestID, _ = RequestID(ctx)
+	snap.UserID, _ = UserID(ctx)
+	snap.AppID, _ = AppID(ctx)
+	snap.Prefix, _ = APIKeyActor(ctx)
+
+	auth, ok := TenantAuth(ctx)
+	if !ok {
+		auth, ok = AppAuth(ctx)
+	}
+	if ok {
+		snap.TenantID = auth.TenantID
+		if auth.AppID != "" {
+			snap.AppID = auth.AppID
+		}
+		if auth.Prefix != "" {
+			snap.Prefix = auth.Prefix
+		}
+		snap.LastUsed = copyTime(auth.LastUsed)
+		snap.CreatedAt = copyTime(auth.CreatedAt)
+	}
+
+	return snap
+}
+
+// Restore stores the values of snap in ctx, so contextx readers (TenantID,
+// TenantAuth, RequestID, ...) see the same identity as where it was captured.
+// Empty values are skipped.
+//
+// Example:
+//
+//	var job Job
+//	_ = json.Unmarshal(msg, &job)
+//	ctx := contextx.Restore(context.Background(), job.Auth)
+func Restore(ctx context.Context, snap AuthSnapshot) context.Context {
+	ctx = WithRequestID(ctx, snap.RequestID)
+	ctx = WithUser(ctx, snap.UserID)
+	ctx = WithApplication(ctx, snap.AppID)
+	if snap.Prefix != "" {
+		ctx = WithAPIKeyPrefix(ctx, snap.Prefix)
+	}
+	if snap.TenantID != "" {
+		ctx = WithTenant(ctx, snap.TenantID)
+		ctx = WithTenantAuthValues(ctx, TenantAuthValues{
+			TenantID:  snap.TenantID,
+			AppID:     snap.AppID,
+			Prefix:    snap.Prefix,
+			LastUsed:  copyTime(snap.LastUsed),
+			CreatedAt: copyTime(snap.CreatedAt),
+		})
+	}
+	return ctx
+}
+
+// copyTime returns a pointer to a copy of *t, or nil.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
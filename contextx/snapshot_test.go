@@ -0,0 +1,79 @@
+package contextx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ctx := context.Background()
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithUser(ctx, "user-1")
+	ctx = WithTenantAuthValues(ctx, TenantAuthValues{
+		TenantID:  "tenant-123",
+		AppID:     "app-456",
+		Prefix:    "sk_live_",
+		CreatedAt: &created,
+	})
+
+	data, err := json.Marshal(Snapshot(ctx))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var snap AuthSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	restored := Restore(context.Background(), snap)
+
+	if id, _ := RequestID(restored); id != "req-1" {
+		t.Fatalf("expected request ID req-1, got %q", id)
+	}
+	if id, _ := UserID(restored); id != "user-1" {
+		t.Fatalf("expected user ID user-1, got %q", id)
+	}
+	if id, _ := TenantID(restored); id != "tenant-123" {
+		t.Fatalf("expected tenant ID tenant-123, got %q", id)
+	}
+	if actor, _ := APIKeyActor(restored); actor != "sk_live_" {
+		t.Fatalf("expected actor sk_live_, got %q", actor)
+	}
+	auth := Must(restored)
+	if auth.AppID != "app-456" || auth.CreatedAt == nil || !auth.CreatedAt.Equal(created) {
+		t.Fatalf("unexpected tenant auth values: %+v", auth)
+	}
+}
+
+func TestSnapshotEmptyContext(t *testing.T) {
+	snap := Snapshot(context.Background())
+	if snap != (AuthSnapshot{}) {
+		t.Fatalf("expected empty snapshot, got %+v", snap)
+	}
+
+	data, _ := json.Marshal(snap)
+	if string(data) != "{}" {
+		t.Fatalf("expected absent values to be omitted, got %s", data)
+	}
+
+	ctx := Restore(context.Background(), snap)
+	if _, ok := TenantAuth(ctx); ok {
+		t.Fatal("expected no tenant after restoring an empty snapshot")
+	}
+}
+
+func TestSnapshotIsDetached(t *testing.T) {
+	lastUsed := time.Now()
+	ctx := WithTenantAuthValues(context.Background(), TenantAuthValues{TenantID: "t", LastUsed: &lastUsed})
+
+	snap := Snapshot(ctx)
+	lastUsed = lastUsed.Add(time.Hour)
+
+	if snap.LastUsed.Equal(lastUsed) {
+		t.Fatal("expected snapshot to hold its own copy of LastUsed")
+	}
+}
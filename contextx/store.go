@@ -0,0 +1,89 @@
+package contextx
+
+import (
+	"context"
+	"sync"
+)
+
+type storeKey struct{}
+
+// store is the mutable, request-scoped value bag installed by WithStore.
+type store struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// WithStore returns a context carrying an empty, mutable value store for handler
+// state that must be shared across middleware within one request. Unlike the other
+// values in this package, store values can be changed after the context is created,
+// and the changes are visible through every context derived from the returned one.
+//
+// If ctx already carries a store, ctx is returned unchanged, so installing it from
+// several middleware is harmless. Create the store once per request; a store shared
+// between requests leaks state across them.
+//
+// The store is safe for concurrent use, so goroutines spawned by a handler may read
+// and write it; values themselves are shared, not copied, and mutable values (maps,
+// pointers) need their own synchronization if modified concurrently.
+//
+// Example:
+//
+//	app.Use(func(c *fiber.Ctx) error {
+//	    c.SetUserContext(contextx.WithStore(c.UserContext()))
+//	    return c.Next()
+//	})
+//	// In a later middleware
+//	contextx.SetStoreValue(ctx, "plan", plan)
+//	// In the handler
+//	plan, ok := contextx.StoreValue[Plan](ctx, "plan")
+func WithStore(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(storeKey{}).(*store); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, storeKey{}, &store{values: make(map[string]any)})
+}
+
+// StoreValue returns the value stored under key as a T. It returns false if ctx has
+// no store, key is absent, or the value is not a T.
+func StoreValue[T any](ctx context.Context, key string) (T, bool) {
+	var zero T
+	s, ok := ctx.Value(storeKey{}).(*store)
+	if !ok {
+		return zero, false
+	}
+
+	s.mu.RLock()
+	v, ok := s.values[key]
+	s.mu.RUnlock()
+	if !ok {
+		return zero, false
+	}
+	t, ok := v.(T)
+	return t, ok
+}
+
+// SetStoreValue stores value under key, replacing any previous value. It returns
+// false, storing nothing, if ctx has no store (see WithStore).
+func SetStoreValue[T any](ctx context.Context, key string, value T) bool {
+	s, ok := ctx.Value(storeKey{}).(*store)
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+	return true
+}
+
+// DeleteStoreValue removes key from the store in ctx, if any.
+func DeleteStoreValue(ctx context.Context, key string) {
+	s, ok := ctx.Value(storeKey{}).(*store)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.values, key)
+	s.mu.Unlock()
+}
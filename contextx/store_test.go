@@ -0,0 +1,47 @@
+package contextx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStoreValues(t *testing.T) {
+	ctx := WithStore(context.Background())
+	derived := WithTenant(ctx, "tenant-1")
+
+	if !SetStoreValue(derived, "attempts", 3) {
+		t.Fatal("expected store to accept value")
+	}
+
+	// Writes through a derived context are visible through the original
+	if v, ok := StoreValue[int](ctx, "attempts"); !ok || v != 3 {
+		t.Fatalf("expected 3, got %v (ok=%v)", v, ok)
+	}
+	if _, ok := StoreValue[string](ctx, "attempts"); ok {
+		t.Fatal("expected type mismatch to report false")
+	}
+	if _, ok := StoreValue[int](ctx, "missing"); ok {
+		t.Fatal("expected missing key to report false")
+	}
+
+	// Installing again keeps the existing store
+	if WithStore(derived) != derived {
+		t.Fatal("expected WithStore to reuse the existing store")
+	}
+
+	DeleteStoreValue(ctx, "attempts")
+	if _, ok := StoreValue[int](derived, "attempts"); ok {
+		t.Fatal("expected value to be deleted")
+	}
+}
+
+func TestStoreValuesWithoutStore(t *testing.T) {
+	ctx := context.Background()
+	if SetStoreValue(ctx, "k", "v") {
+		t.Fatal("expected set without store to fail")
+	}
+	if _, ok := StoreValue[string](ctx, "k"); ok {
+		t.Fatal("expected no value without store")
+	}
+	DeleteStoreValue(ctx, "k") // Must not panic
+}
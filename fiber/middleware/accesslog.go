@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"errors"
+	"sort"
 	"time"
 
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/cubetiqlabs/gopkg/util"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -21,11 +25,43 @@ type AccessLogConfig struct {
 	// Example: []string{"X-Request-ID", "User-Agent"}
 	IncludeHeaders []string
 
+	// IncludeContextFields adds identity values from c.UserContext() (via contextx.Fields)
+	// as individual fields: tenant, app, user (default: false)
+	IncludeContextFields bool
+
+	// SlowThreshold escalates requests that take longer than this to at least Warn
+	// and adds a slow=true field; a higher level from LevelResolver still wins (default: 0 = disabled)
+	SlowThreshold time.Duration
+
+	// Message is the log message for each request (default: "http request")
+	// The key the message is written under (e.g. "msg") is set by the logger's
+	// encoder config (zapcore.EncoderConfig.MessageKey), not by this middleware.
+	Message string
+
+	// IncludeRoute adds a route field with the matched route template, e.g. "/users/:id",
+	// the same value the Metrics middleware labels requests with. Unlike path it has low
+	// cardinality, so logs can be aggregated by endpoint. Requests that match no route
+	// have no route field (default: false)
+	IncludeRoute bool
+
+	// OmitPath drops the concrete path field for requests that matched a route; use it
+	// with IncludeRoute to log only the template. Unmatched requests keep path (default: false)
+	OmitPath bool
+
+	// FieldNames renames log fields to match a central log schema (default: nil = default names)
+	// Keys are the default names: method, path, route, status, duration, ip, slow, error,
+	// tenant, app, user, and header_<Header> for IncludeHeaders.
+	// Example: map[string]string{"method": "http.method", "status": "http.status_code"}
+	FieldNames map[string]string
+
 	// Skip is a function to skip logging for certain requests
 	// Example: func(c *fiber.Ctx) bool { return c.Path() == "/health" }
 	Skip func(c *fiber.Ctx) bool
 }
 
+// defaultAccessLogMessage is the log message used when AccessLogConfig.Message is empty.
+const defaultAccessLogMessage = "http request"
+
 // AccessLog returns a middleware with default configuration.
 // You must provide a logger via AccessLogWithConfig if you want to use this.
 //
@@ -47,15 +83,40 @@ func AccessLog() fiber.Handler {
 //	app.Use(middleware.AccessLogWithConfig(&middleware.AccessLogConfig{
 //	    Logger: logger,
 //	    IncludeHeaders: []string{"X-Request-ID", "User-Agent"},
+//	    SlowThreshold: 2 * time.Second, // Log slow 200s at warn
+//	    IncludeRoute: true,             // route="/users/:id" alongside path="/users/42"
 //	    Skip: func(c *fiber.Ctx) bool {
 //	        return c.Path() == "/health" || c.Path() == "/metrics"
 //	    },
 //	}))
+//
+// Conforming to a central log schema:
+//
+//	app.Use(middleware.AccessLogWithConfig(&middleware.AccessLogConfig{
+//	    Logger:  logger, // encoder configured with MessageKey: "event"
+//	    Message: "http_access",
+//	    FieldNames: map[string]string{
+//	        "method": "http.method",
+//	        "status": "http.status_code",
+//	        "path":   "url.path",
+//	    },
+//	}))
 func AccessLogWithConfig(cfg *AccessLogConfig) fiber.Handler {
 	// Set defaults
 	if cfg.LevelResolver == nil {
 		cfg.LevelResolver = defaultLevelResolver
 	}
+	if cfg.Message == "" {
+		cfg.Message = defaultAccessLogMessage
+	}
+
+	// name returns the configured name for a default field name
+	name := func(field string) string {
+		if renamed := cfg.FieldNames[field]; renamed != "" {
+			return renamed
+		}
+		return field
+	}
 
 	return func(c *fiber.Ctx) error {
 		// Skip if configured
@@ -63,6 +124,12 @@ func AccessLogWithConfig(cfg *AccessLogConfig) fiber.Handler {
 			return c.Next()
 		}
 
+		// c.Route() is this middleware's own mount until the router finds a handler
+		var mount *fiber.Route
+		if cfg.IncludeRoute {
+			mount = c.Route()
+		}
+
 		start := time.Now()
 		err := c.Next()
 		duration := time.Since(start)
@@ -70,43 +137,64 @@ func AccessLogWithConfig(cfg *AccessLogConfig) fiber.Handler {
 		// Determine status code
 		status := determineStatus(c, err)
 
-		// Determine log level
+		// Determine log level, escalating slow requests to at least Warn
 		level := cfg.LevelResolver(status, err)
+		slow := cfg.SlowThreshold > 0 && duration > cfg.SlowThreshold
+		if slow && level < zapcore.WarnLevel {
+			level = zapcore.WarnLevel
+		}
 
 		// Build log fields
-		fields := []zap.Field{
-			zap.String("method", c.Method()),
-			zap.String("path", c.Path()),
-			zap.Int("status", status),
-			zap.Duration("duration", duration),
-			zap.String("ip", c.IP()),
+		fields := []zap.Field{zap.String(name("method"), c.Method())}
+		route := ""
+		if cfg.IncludeRoute {
+			route = matchedRoute(c, mount)
+		}
+		if route == "" || !cfg.OmitPath {
+			fields = append(fields, zap.String(name("path"), c.Path()))
+		}
+		if route != "" {
+			fields = append(fields, zap.String(name("route"), route))
+		}
+		fields = append(fields,
+			zap.Int(name("status"), status),
+			zap.Duration(name("duration"), duration),
+			zap.String(name("ip"), c.IP()),
+		)
+		if slow {
+			fields = append(fields, zap.Bool(name("slow"), true))
 		}
 
 		// Add configured headers
 		for _, header := range cfg.IncludeHeaders {
 			if val := c.Get(header); val != "" {
-				fields = append(fields, zap.String("header_"+header, val))
+				fields = append(fields, zap.String(name("header_"+header), val))
 			}
 		}
 
+		// Add identity values from context
+		if cfg.IncludeContextFields {
+			fields = appendContextFields(fields, contextx.Fields(c.UserContext()), name)
+		}
+
 		// Add error if present
 		if err != nil {
-			fields = append(fields, zap.Error(err))
+			fields = append(fields, zap.NamedError(name("error"), err))
 		}
 
 		// Log based on level
 		if cfg.Logger != nil {
 			switch level {
 			case zapcore.DebugLevel:
-				cfg.Logger.Debug("http request", fields...)
+				cfg.Logger.Debug(cfg.Message, fields...)
 			case zapcore.InfoLevel:
-				cfg.Logger.Info("http request", fields...)
+				cfg.Logger.Info(cfg.Message, fields...)
 			case zapcore.WarnLevel:
-				cfg.Logger.Warn("http request", fields...)
+				cfg.Logger.Warn(cfg.Message, fields...)
 			case zapcore.ErrorLevel:
-				cfg.Logger.Error("http request", fields...)
+				cfg.Logger.Error(cfg.Message, fields...)
 			default:
-				cfg.Logger.Info("http request", fields...)
+				cfg.Logger.Info(cfg.Message, fields...)
 			}
 		}
 
@@ -114,6 +202,32 @@ func AccessLogWithConfig(cfg *AccessLogConfig) fiber.Handler {
 	}
 }
 
+// appendContextFields appends context identity values as zap fields in stable key order,
+// naming each field via name.
+func appendContextFields(fields []zap.Field, values map[string]string, name func(string) string) []zap.Field {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fields = append(fields, zap.String(name(k), values[k]))
+	}
+	return fields
+}
+
+// matchedRoute returns the template of the route that handled the request, or "" if
+// no route matched. mount is the route c.Route() returned before c.Next(); it is
+// still returned afterwards when the router found no handler.
+func matchedRoute(c *fiber.Ctx, mount *fiber.Route) string {
+	route := c.Route()
+	if route == nil || route == mount {
+		return ""
+	}
+	return route.Path
+}
+
 // defaultLevelResolver returns appropriate log level based on status code.
 func defaultLevelResolver(status int, err error) zapcore.Level {
 	switch {
@@ -128,13 +242,24 @@ func defaultLevelResolver(status int, err error) zapcore.Level {
 	}
 }
 
-// determineStatus extracts the response status code.
+// determineStatus extracts the response status code. Errors are mapped the way
+// ErrorHandler renders them, since it runs after the middleware chain returns.
 func determineStatus(c *fiber.Ctx, err error) int {
-	if err != nil {
-		if e, ok := err.(*fiber.Error); ok {
-			return e.Code
-		}
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
 		return fiber.StatusInternalServerError
 	}
-	return c.Response().StatusCode()
+	var validationErr *util.ValidationError
+	if errors.As(err, &validationErr) {
+		return fiber.StatusUnprocessableEntity
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
 }
@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/cubetiqlabs/gopkg/logging/logtest"
+	"github.com/cubetiqlabs/gopkg/util"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAccessLogIncludeContextFields(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New()
+	app.Use(AccessLogWithConfig(&AccessLogConfig{
+		Logger:               logger,
+		IncludeContextFields: true,
+	}))
+	app.Get("/test", func(c *fiber.Ctx) error {
+		ctx := contextx.WithTenant(c.UserContext(), "tenant-123")
+		c.SetUserContext(contextx.WithUser(ctx, "user-1"))
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/test", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+	ctxMap := entries[0].ContextMap()
+	if ctxMap["tenant"] != "tenant-123" || ctxMap["user"] != "user-1" {
+		t.Fatalf("expected identity fields, got %v", ctxMap)
+	}
+}
+
+func TestAccessLogContextFieldsOffByDefault(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New()
+	app.Use(AccessLogWithConfig(&AccessLogConfig{Logger: logger}))
+	app.Get("/test", func(c *fiber.Ctx) error {
+		c.SetUserContext(contextx.WithTenant(c.UserContext(), "tenant-123"))
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/test", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	if _, ok := logs.All()[0].ContextMap()["tenant"]; ok {
+		t.Fatal("expected tenant field to be omitted by default")
+	}
+}
+
+func TestAccessLogSlowThreshold(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New()
+	app.Use(AccessLogWithConfig(&AccessLogConfig{
+		Logger:        logger,
+		SlowThreshold: 10 * time.Millisecond,
+	}))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		time.Sleep(20 * time.Millisecond)
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/slow-error", func(c *fiber.Ctx) error {
+		time.Sleep(20 * time.Millisecond)
+		return fiber.ErrInternalServerError
+	})
+	app.Get("/fast", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	for _, path := range []string{"/slow", "/slow-error", "/fast"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+	}
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 log entries, got %d", len(entries))
+	}
+	if entries[0].Level != zapcore.WarnLevel || entries[0].ContextMap()["slow"] != true {
+		t.Fatalf("expected slow 200 at warn with slow=true, got %v %v", entries[0].Level, entries[0].ContextMap())
+	}
+	if entries[1].Level != zapcore.ErrorLevel {
+		t.Fatalf("expected resolver's error level to win, got %v", entries[1].Level)
+	}
+	if _, ok := entries[2].ContextMap()["slow"]; ok || entries[2].Level != zapcore.InfoLevel {
+		t.Fatalf("expected fast request at info without slow field, got %v %v", entries[2].Level, entries[2].ContextMap())
+	}
+}
+
+func TestAccessLogStatusMatchesErrorHandler(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Use(AccessLogWithConfig(&AccessLogConfig{Logger: logger}))
+	app.Get("/invalid", func(c *fiber.Ctx) error {
+		verr := util.NewValidationError()
+		verr.Add("email", "is required")
+		return fmt.Errorf("create user: %w", verr)
+	})
+	app.Get("/wrapped", func(c *fiber.Ctx) error {
+		return fmt.Errorf("load user: %w", fiber.ErrNotFound)
+	})
+
+	tests := []struct {
+		path   string
+		status int64
+	}{
+		{"/invalid", fiber.StatusUnprocessableEntity},
+		{"/wrapped", fiber.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+		if int64(resp.StatusCode) != tt.status {
+			t.Fatalf("%s: expected response status %d, got %d", tt.path, tt.status, resp.StatusCode)
+		}
+
+		entries := logs.TakeAll()
+		if len(entries) != 1 {
+			t.Fatalf("%s: expected 1 log entry, got %d", tt.path, len(entries))
+		}
+		if got := entries[0].ContextMap()["status"]; got != tt.status {
+			t.Fatalf("%s: expected logged status %d, got %v", tt.path, tt.status, got)
+		}
+		if entries[0].Level != zapcore.WarnLevel {
+			t.Fatalf("%s: expected warn level, got %s", tt.path, entries[0].Level)
+		}
+	}
+}
+
+func TestAccessLogMessageAndFieldNames(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New()
+	app.Use(AccessLogWithConfig(&AccessLogConfig{
+		Logger:  logger,
+		Message: "http_access",
+		FieldNames: map[string]string{
+			"method": "http.method",
+			"status": "http.status_code",
+			"error":  "error.message",
+		},
+	}))
+	app.Get("/fail", func(c *fiber.Ctx) error { return fiber.ErrBadRequest })
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/fail", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+	if entries[0].Message != "http_access" {
+		t.Fatalf("expected message http_access, got %q", entries[0].Message)
+	}
+	fields := entries[0].ContextMap()
+	if fields["http.method"] != "GET" || fields["http.status_code"] != int64(400) {
+		t.Fatalf("expected renamed method and status fields, got %v", fields)
+	}
+	if _, ok := fields["error.message"]; !ok {
+		t.Fatalf("expected renamed error field, got %v", fields)
+	}
+	if _, ok := fields["method"]; ok {
+		t.Fatalf("expected default method name to be replaced, got %v", fields)
+	}
+	if fields["path"] != "/fail" {
+		t.Fatalf("expected unmapped fields to keep default names, got %v", fields)
+	}
+}
+
+func TestAccessLogIncludeRoute(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New()
+	app.Use(AccessLogWithConfig(&AccessLogConfig{
+		Logger:       logger,
+		IncludeRoute: true,
+		OmitPath:     true,
+	}))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, path := range []string{"/users/42", "/missing/1"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d", len(entries))
+	}
+
+	matched := entries[0].ContextMap()
+	if matched["route"] != "/users/:id" {
+		t.Fatalf("expected route template, got %v", matched)
+	}
+	if _, ok := matched["path"]; ok {
+		t.Fatalf("expected path omitted for matched route, got %v", matched)
+	}
+
+	// Unmatched requests have no template and keep the concrete path
+	unmatched := entries[1].ContextMap()
+	if _, ok := unmatched["route"]; ok || unmatched["path"] != "/missing/1" {
+		t.Fatalf("expected path only for unmatched request, got %v", unmatched)
+	}
+}
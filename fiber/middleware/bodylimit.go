@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrBodyTooLarge is returned by streamed body reads that exceed the BodyLimit.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// bodyStreamLocal is the c.Locals key holding the limited body stream.
+const bodyStreamLocal = "body_limit_stream"
+
+// BodyLimit returns a middleware that rejects request bodies larger than maxBytes
+// with 413 Request Entity Too Large. The error is a *fiber.Error, so ErrorHandler
+// renders it with the standard JSON envelope.
+//
+// Enforcement:
+// - Requests with a Content-Length above the limit are rejected before the handler runs
+// - Buffered bodies without a Content-Length (chunked) are checked by size
+// - Streamed chunked bodies (StreamRequestBody) fail BodyStream reads with ErrBodyTooLarge
+//
+// The limited stream is not installed on the request, since replacing a fasthttp
+// request stream releases the original one. Handlers, including body parsers, that
+// read c.Body() or c.Context().RequestBodyStream() bypass the streamed limit.
+//
+// Note: Fiber's own fiber.Config.BodyLimit (default 4MB) still applies first when
+// bodies are buffered; use this middleware for tighter per-route limits.
+//
+// Example usage:
+//
+//	app.Use(middleware.BodyLimit(1 << 20)) // 1MB
+//	uploads := app.Group("/uploads", middleware.BodyLimit(50<<20))
+func BodyLimit(maxBytes int64) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if maxBytes <= 0 {
+			return c.Next()
+		}
+
+		req := c.Request()
+
+		// Fast path: declared length
+		if n := req.Header.ContentLength(); n > 0 && int64(n) > maxBytes {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, "request body too large")
+		}
+
+		// Streamed body without a length: enforce during read. A declared length is
+		// already within the limit, and fasthttp stops the stream there.
+		if req.IsBodyStream() {
+			if req.Header.ContentLength() >= 0 {
+				return c.Next()
+			}
+			limited := &limitedBodyReader{r: c.Context().RequestBodyStream(), remaining: maxBytes}
+			c.Locals(bodyStreamLocal, limited)
+			err := c.Next()
+			if limited.remaining < 0 {
+				// The rest of the body is unread; don't parse it as the next request
+				c.Context().SetConnectionClose()
+			}
+			return err
+		}
+
+		// Buffered body (e.g. chunked uploads already read by fasthttp)
+		if int64(len(req.Body())) > maxBytes {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, "request body too large")
+		}
+
+		return c.Next()
+	}
+}
+
+// BodyStream returns the request body stream limited by BodyLimit, or the request's
+// own stream (nil for buffered bodies) if BodyLimit did not wrap it. Reads past the
+// limit fail with ErrBodyTooLarge.
+//
+// Example usage:
+//
+//	app := fiber.New(fiber.Config{StreamRequestBody: true})
+//	app.Post("/uploads", middleware.BodyLimit(50<<20), func(c *fiber.Ctx) error {
+//	    if _, err := io.Copy(dst, middleware.BodyStream(c)); err != nil {
+//	        if errors.Is(err, middleware.ErrBodyTooLarge) {
+//	            return fiber.ErrRequestEntityTooLarge
+//	        }
+//	        return err
+//	    }
+//	    return c.SendStatus(fiber.StatusCreated)
+//	})
+func BodyStream(c *fiber.Ctx) io.Reader {
+	if r, ok := c.Locals(bodyStreamLocal).(*limitedBodyReader); ok {
+		return r
+	}
+	if s := c.Context().RequestBodyStream(); s != nil {
+		return s
+	}
+	return nil
+}
+
+// limitedBodyReader fails reads with ErrBodyTooLarge once more than remaining bytes are read.
+type limitedBodyReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// Read implements io.Reader.
+func (l *limitedBodyReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	// Read one byte past the limit to detect overflow
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrBodyTooLarge
+	}
+	return n, err
+}
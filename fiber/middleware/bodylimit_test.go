@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestBodyLimitRejectsLargeContentLength(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Use(BodyLimit(4))
+	app.Post("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/test", strings.NewReader("too large")))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", resp.StatusCode)
+	}
+}
+
+func TestBodyLimitAllowsSmallBody(t *testing.T) {
+	app := fiber.New()
+	app.Use(BodyLimit(16))
+	app.Post("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/test", strings.NewReader("small")))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestLimitedBodyReader(t *testing.T) {
+	r := &limitedBodyReader{r: strings.NewReader("0123456789"), remaining: 4}
+
+	data, err := io.ReadAll(r)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("expected ErrBodyTooLarge, got %v", err)
+	}
+	if string(data) != "0123" {
+		t.Fatalf("expected only limit bytes to be returned, got %q", data)
+	}
+
+	r = &limitedBodyReader{r: strings.NewReader("0123"), remaining: 4}
+	if data, err := io.ReadAll(r); err != nil || string(data) != "0123" {
+		t.Fatalf("expected body within limit to read fully, got %q, %v", data, err)
+	}
+}
+
+func TestBodyLimitStreamedChunkedBody(t *testing.T) {
+	app := fiber.New(fiber.Config{StreamRequestBody: true, ErrorHandler: ErrorHandler()})
+	app.Use(BodyLimit(8))
+	app.Post("/upload", func(c *fiber.Ctx) error {
+		data, err := io.ReadAll(BodyStream(c))
+		if errors.Is(err, ErrBodyTooLarge) {
+			return fiber.ErrRequestEntityTooLarge
+		}
+		if err != nil {
+			return err
+		}
+		return c.SendString(string(data))
+	})
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{"small", fiber.StatusOK},
+		{strings.Repeat("x", 64<<10), fiber.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/upload", io.NopCloser(strings.NewReader(tt.body)))
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Fatalf("body of %d bytes: expected %d, got %d", len(tt.body), tt.want, resp.StatusCode)
+		}
+		if tt.want == fiber.StatusOK {
+			if got, _ := io.ReadAll(resp.Body); string(got) != tt.body {
+				t.Fatalf("expected echoed body %q, got %q", tt.body, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContextBridgeConfig defines which c.Locals keys ContextBridge copies into c.UserContext().
+// Empty keys fall back to the defaults below.
+type ContextBridgeConfig struct {
+	// RequestIDLocal holds the request ID (default: "request_id", as set by RequestID)
+	RequestIDLocal string
+	// TenantLocal holds the tenant ID string (default: "tenant_id")
+	TenantLocal string
+	// AppLocal holds the application ID string (default: "app_id")
+	AppLocal string
+	// UserLocal holds the authenticated user ID string (default: "user_id")
+	UserLocal string
+	// AuthLocal holds a contextx.TenantAuthValues (default: "tenant_auth")
+	AuthLocal string
+}
+
+// ContextBridge returns a middleware that copies request-scoped values from c.Locals
+// into c.UserContext() using the contextx setters, so code reading context.Context
+// (logging, Metrics, AccessLog with IncludeContextFields) sees the same values as
+// code reading locals.
+//
+// Register it after RequestID and any auth middleware that populates locals:
+//
+//	app.Use(middleware.RequestID())
+//	app.Use(authMiddleware) // sets c.Locals("tenant_id", ...)
+//	app.Use(middleware.ContextBridge())
+//	app.Use(middleware.Metrics(reg))
+func ContextBridge() fiber.Handler {
+	return ContextBridgeWithConfig(ContextBridgeConfig{})
+}
+
+// ContextBridgeWithConfig returns a ContextBridge middleware with custom locals keys.
+//
+// Example usage:
+//
+//	app.Use(middleware.ContextBridgeWithConfig(middleware.ContextBridgeConfig{
+//	    TenantLocal: "tenant",
+//	    UserLocal:   "uid",
+//	}))
+func ContextBridgeWithConfig(cfg ContextBridgeConfig) fiber.Handler {
+	if cfg.RequestIDLocal == "" {
+		cfg.RequestIDLocal = "request_id"
+	}
+	if cfg.TenantLocal == "" {
+		cfg.TenantLocal = "tenant_id"
+	}
+	if cfg.AppLocal == "" {
+		cfg.AppLocal = "app_id"
+	}
+	if cfg.UserLocal == "" {
+		cfg.UserLocal = "user_id"
+	}
+	if cfg.AuthLocal == "" {
+		cfg.AuthLocal = "tenant_auth"
+	}
+
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		if rid := localString(c, cfg.RequestIDLocal); rid != "" {
+			ctx = contextx.WithRequestID(ctx, rid)
+		}
+		if auth, ok := c.Locals(cfg.AuthLocal).(contextx.TenantAuthValues); ok {
+			ctx = contextx.WithTenantAuthValues(ctx, auth)
+			ctx = contextx.WithTenant(ctx, auth.TenantID)
+			ctx = contextx.WithApplication(ctx, auth.AppID)
+		}
+		if tenantID := localString(c, cfg.TenantLocal); tenantID != "" {
+			ctx = contextx.WithTenant(ctx, tenantID)
+		}
+		if appID := localString(c, cfg.AppLocal); appID != "" {
+			ctx = contextx.WithApplication(ctx, appID)
+		}
+		if userID := localString(c, cfg.UserLocal); userID != "" {
+			ctx = contextx.WithUser(ctx, userID)
+		}
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
+
+// localString returns the string stored in c.Locals(key), or "" if absent or not a string.
+// The value is cloned because locals often alias request buffers that Fiber reuses,
+// while the context may outlive the request (e.g. in background goroutines).
+func localString(c *fiber.Ctx, key string) string {
+	s, _ := c.Locals(key).(string)
+	return strings.Clone(s)
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestContextBridgeCopiesLocals(t *testing.T) {
+	var fields map[string]string
+	var rid, tenant string
+
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("tenant_id", "t1")
+		c.Locals("user_id", "u1")
+		return c.Next()
+	})
+	app.Use(ContextBridge())
+	app.Get("/", func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		rid, _ = contextx.RequestID(ctx)
+		tenant, _ = contextx.TenantID(ctx)
+		fields = contextx.Fields(ctx)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "rid-123")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	if rid != "rid-123" {
+		t.Fatalf("expected request ID in context, got %q", rid)
+	}
+	if tenant != "t1" {
+		t.Fatalf("expected tenant in context, got %q", tenant)
+	}
+	if fields["user"] != "u1" || fields["tenant"] != "t1" {
+		t.Fatalf("unexpected context fields: %v", fields)
+	}
+}
+
+func TestContextBridgeAuthValues(t *testing.T) {
+	var auth contextx.TenantAuthValues
+	var tenant string
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("auth", contextx.TenantAuthValues{TenantID: "t2", AppID: "a2", Prefix: "pk_"})
+		return c.Next()
+	})
+	app.Use(ContextBridgeWithConfig(ContextBridgeConfig{AuthLocal: "auth"}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		auth, _ = contextx.TenantAuth(c.UserContext())
+		tenant, _ = contextx.TenantID(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if auth.Prefix != "pk_" || auth.AppID != "a2" || tenant != "t2" {
+		t.Fatalf("unexpected auth values: %+v tenant=%q", auth, tenant)
+	}
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultDumpMaxBodyBytes is the body capture limit used when DumpConfig.MaxBodyBytes is 0.
+const defaultDumpMaxBodyBytes = 4096
+
+// redactedValue replaces the values of redacted headers in dumps.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactHeaders are always redacted by Dump, in addition to DumpConfig.RedactHeaders.
+var defaultRedactHeaders = []string{
+	fiber.HeaderAuthorization,
+	fiber.HeaderProxyAuthorization,
+	fiber.HeaderCookie,
+	fiber.HeaderSetCookie,
+	"X-API-Key",
+}
+
+// DumpConfig defines configuration for the Dump middleware.
+type DumpConfig struct {
+	// Logger receives one debug entry per request (required)
+	Logger *zap.Logger
+
+	// MaxBodyBytes caps how much of each request and response body is logged (default: 4096)
+	// Longer bodies are truncated and flagged with *_body_truncated=true.
+	MaxBodyBytes int
+
+	// RedactHeaders are additional headers whose values are replaced with "[REDACTED]"
+	// (case-insensitive). Authorization, Proxy-Authorization, Cookie, Set-Cookie, and
+	// X-API-Key are always redacted.
+	RedactHeaders []string
+
+	// Enabled is checked on every request; dumping is skipped when it returns false (default: nil = always)
+	// Use it to toggle dumping at runtime, e.g. from a config flag.
+	Enabled func() bool
+}
+
+// Dump returns a debugging middleware that logs full request and response details
+// at debug level: method, URL, status, headers (with sensitive values redacted), and
+// bodies truncated to MaxBodyBytes. It is meant to be switched on temporarily while
+// investigating integration issues; use AccessLog for routine request logging.
+//
+// Behaviour:
+// - Nothing is captured when Enabled returns false or the logger has debug disabled
+// - Streamed request and response bodies are never read, so streaming keeps working; they're logged as *_body_streamed=true
+// - Requests that return an error are dumped with the error; the status is resolved like AccessLog does
+//
+// Panics if Logger is nil.
+//
+// Example usage:
+//
+//	app.Use(middleware.Dump(middleware.DumpConfig{
+//	    Logger:        logger,
+//	    MaxBodyBytes:  8 << 10,
+//	    RedactHeaders: []string{"X-Partner-Token"},
+//	    Enabled:       func() bool { return cfg.GetBool("debug.dump_http") },
+//	}))
+func Dump(cfg DumpConfig) fiber.Handler {
+	if cfg.Logger == nil {
+		panic("dump: Logger is required")
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = defaultDumpMaxBodyBytes
+	}
+
+	redact := make(map[string]bool, len(defaultRedactHeaders)+len(cfg.RedactHeaders))
+	for _, h := range defaultRedactHeaders {
+		redact[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, h := range cfg.RedactHeaders {
+		redact[http.CanonicalHeaderKey(h)] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Enabled != nil && !cfg.Enabled() {
+			return c.Next()
+		}
+		if !cfg.Logger.Core().Enabled(zapcore.DebugLevel) {
+			return c.Next()
+		}
+
+		req := c.Request()
+		fields := []zap.Field{
+			zap.String("method", strings.Clone(c.Method())),
+			zap.String("url", strings.Clone(c.OriginalURL())), // May alias a reused Fiber buffer
+		}
+
+		headers := make(dumpHeaders)
+		req.Header.VisitAll(func(k, v []byte) {
+			headers.add(string(k), string(v), redact)
+		})
+		fields = append(fields, zap.Object("request_headers", headers))
+
+		// Capture the request body before the handler runs, in case it consumes it
+		if req.IsBodyStream() {
+			fields = append(fields, zap.Bool("request_body_streamed", true))
+		} else {
+			fields = appendDumpBody(fields, "request", req.Body(), cfg.MaxBodyBytes)
+		}
+
+		err := c.Next()
+
+		resp := c.Response()
+		fields = append(fields, zap.Int("status", determineStatus(c, err)))
+
+		respHeaders := make(dumpHeaders)
+		resp.Header.VisitAll(func(k, v []byte) {
+			respHeaders.add(string(k), string(v), redact)
+		})
+		fields = append(fields, zap.Object("response_headers", respHeaders))
+
+		if resp.IsBodyStream() {
+			fields = append(fields, zap.Bool("response_body_streamed", true))
+		} else {
+			fields = appendDumpBody(fields, "response", resp.Body(), cfg.MaxBodyBytes)
+		}
+
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+
+		cfg.Logger.Debug("http dump", fields...)
+		return err
+	}
+}
+
+// appendDumpBody adds the body (copied and truncated to max bytes) and its size,
+// flagging truncation, under fields prefixed with prefix.
+func appendDumpBody(fields []zap.Field, prefix string, body []byte, max int) []zap.Field {
+	fields = append(fields, zap.Int(prefix+"_body_size", len(body)))
+	if len(body) > max {
+		return append(fields,
+			zap.String(prefix+"_body", string(body[:max])),
+			zap.Bool(prefix+"_body_truncated", true),
+		)
+	}
+	return append(fields, zap.String(prefix+"_body", string(body)))
+}
+
+// dumpHeaders holds header values for logging, keyed by canonical name.
+// Repeated headers are joined with ", ".
+type dumpHeaders map[string]string
+
+// add records a header value, redacting it if the header is in redact.
+func (h dumpHeaders) add(name, value string, redact map[string]bool) {
+	name = http.CanonicalHeaderKey(name)
+	if redact[name] {
+		value = redactedValue
+	}
+	if prev, ok := h[name]; ok && !redact[name] {
+		value = prev + ", " + value
+	}
+	h[name] = value
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler, writing headers in sorted order.
+func (h dumpHeaders) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		enc.AddString(name, h[name])
+	}
+	return nil
+}
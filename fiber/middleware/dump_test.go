@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bufio"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/logging/logtest"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDumpLogsRedactedRequestAndResponse(t *testing.T) {
+	logger, logs := logtest.NewObserver("debug")
+
+	app := fiber.New()
+	app.Use(Dump(DumpConfig{
+		Logger:        logger,
+		MaxBodyBytes:  5,
+		RedactHeaders: []string{"x-partner-token"},
+	}))
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		c.Set("Set-Cookie", "session=secret")
+		return c.Status(fiber.StatusCreated).SendString("created")
+	})
+
+	req := httptest.NewRequest("POST", "/orders?debug=1", strings.NewReader(`{"id":1}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Partner-Token", "secret")
+	req.Header.Set("X-Trace", "abc")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	entries := logs.FilterMessage("http dump").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 dump entry, got %d", len(entries))
+	}
+	m := entries[0].ContextMap()
+
+	if m["url"] != "/orders?debug=1" || m["status"] != int64(fiber.StatusCreated) {
+		t.Fatalf("unexpected url/status: %v %v", m["url"], m["status"])
+	}
+	if m["request_body"] != `{"id"` || m["request_body_truncated"] != true || m["request_body_size"] != int64(8) {
+		t.Fatalf("expected truncated request body, got %v", m)
+	}
+	if m["response_body"] != "creat" {
+		t.Fatalf("expected truncated response body, got %v", m["response_body"])
+	}
+
+	reqHeaders := m["request_headers"].(map[string]interface{})
+	if reqHeaders["Authorization"] != redactedValue || reqHeaders["X-Partner-Token"] != redactedValue {
+		t.Fatalf("expected sensitive request headers to be redacted, got %v", reqHeaders)
+	}
+	if reqHeaders["X-Trace"] != "abc" {
+		t.Fatalf("expected other headers to be logged, got %v", reqHeaders)
+	}
+	if respHeaders := m["response_headers"].(map[string]interface{}); respHeaders["Set-Cookie"] != redactedValue {
+		t.Fatalf("expected Set-Cookie to be redacted, got %v", respHeaders)
+	}
+}
+
+func TestDumpEnabledToggle(t *testing.T) {
+	logger, logs := logtest.NewObserver("debug")
+
+	enabled := false
+	app := fiber.New()
+	app.Use(Dump(DumpConfig{Logger: logger, Enabled: func() bool { return enabled }}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Fatalf("expected no dump while disabled, got %d entries", logs.Len())
+	}
+
+	enabled = true
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("expected a dump once enabled, got %d entries", logs.Len())
+	}
+}
+
+func TestDumpLeavesStreamedResponseIntact(t *testing.T) {
+	logger, logs := logtest.NewObserver("debug")
+
+	app := fiber.New()
+	app.Use(Dump(DumpConfig{Logger: logger}))
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			_, _ = w.WriteString("chunk-1;chunk-2")
+		})
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/stream", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "chunk-1;chunk-2" {
+		t.Fatalf("expected streamed body to reach the client, got %q", body)
+	}
+
+	m := logs.All()[0].ContextMap()
+	if m["response_body_streamed"] != true {
+		t.Fatalf("expected response to be flagged as streamed, got %v", m)
+	}
+	if _, ok := m["response_body"]; ok {
+		t.Fatal("expected streamed body not to be captured")
+	}
+}
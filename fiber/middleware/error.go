@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
+	"runtime/debug"
 
+	"github.com/cubetiqlabs/gopkg/util"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
@@ -11,6 +14,51 @@ import (
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+
+	// Fields lists invalid fields for validation errors (*util.ValidationError)
+	Fields []util.FieldError `json:"fields,omitempty"`
+}
+
+// PanicError wraps a value recovered from a panic in a handler.
+// It is produced by the Recover middleware and recognized by ErrorHandler,
+// which logs it with the captured stack and responds with a generic 500.
+type PanicError struct {
+	Value interface{} // Value passed to panic
+	Stack []byte      // Stack trace captured at recovery
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recover returns a middleware that converts panics in downstream handlers into
+// *PanicError, so they flow through the ErrorHandler's JSON envelope instead of
+// bypassing it.
+//
+// Example usage:
+//
+//	app := fiber.New(fiber.Config{
+//	    ErrorHandler: middleware.ErrorHandlerWithConfig(middleware.ErrorHandlerConfig{Logger: logger}),
+//	})
+//	app.Use(middleware.Recover())
+func Recover() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		return c.Next()
+	}
 }
 
 // ErrorHandlerConfig defines configuration for the error handler.
@@ -28,6 +76,8 @@ type ErrorHandlerConfig struct {
 //
 // Error handling rules:
 // - Fiber errors (*fiber.Error) are considered safe to expose
+// - Validation errors (*util.ValidationError) return 422 with the field list
+// - Recovered panics (*PanicError, see Recover) are logged with stack and return a generic 500
 // - All other errors are logged and return generic "Internal Server Error"
 //
 // Example usage:
@@ -59,6 +109,34 @@ func ErrorHandlerWithConfig(cfg ErrorHandlerConfig) fiber.ErrorHandler {
 	}
 
 	return func(c *fiber.Ctx, err error) error {
+		// Recovered panics are logged with their stack and never expose details, even
+		// when the panic value is a *fiber.Error or validation error (see Unwrap)
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			if cfg.Logger != nil {
+				cfg.Logger.Error("panic recovered",
+					zap.String("path", c.Path()),
+					zap.String("method", c.Method()),
+					zap.Any("panic", panicErr.Value),
+					zap.ByteString("stack", panicErr.Stack),
+				)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+				Error:   "Internal Server Error",
+				Message: "An unexpected error occurred",
+			})
+		}
+
+		// Validation errors carry client-facing field messages
+		var validationErr *util.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(ErrorResponse{
+				Error:   "Unprocessable Entity",
+				Message: "validation failed",
+				Fields:  validationErr.Fields,
+			})
+		}
+
 		// Fiber errors are considered safe to expose (they're explicitly created by handlers)
 		var fiberErr *fiber.Error
 		if errors.As(err, &fiberErr) {
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/logging/logtest"
+	"github.com/cubetiqlabs/gopkg/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestErrorHandlerFiberError(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Get("/test", func(c *fiber.Ctx) error { return fiber.NewError(fiber.StatusNotFound, "user not found") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error != "user not found" {
+		t.Fatalf("expected fiber message to be exposed, got %q", body.Error)
+	}
+}
+
+func TestErrorHandlerValidationError(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Post("/users", func(c *fiber.Ctx) error {
+		return util.NewValidationError().
+			Add("email", "is required").
+			AddCode("age", "min", "must be at least 18")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/users", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Fields) != 2 || body.Fields[0].Field != "email" || body.Fields[1].Code != "min" {
+		t.Fatalf("unexpected fields: %+v", body.Fields)
+	}
+}
+
+func TestRecoverPanicRendersEnvelope(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandlerWithConfig(ErrorHandlerConfig{Logger: logger})})
+	app.Use(Recover())
+	app.Get("/test", func(c *fiber.Ctx) error { panic("secret detail") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error != "Internal Server Error" {
+		t.Fatalf("expected generic error, got %q", body.Error)
+	}
+
+	entries := logs.FilterMessage("panic recovered").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected panic to be logged once, got %d", len(entries))
+	}
+	if _, ok := entries[0].ContextMap()["stack"]; !ok {
+		t.Fatal("expected stack to be logged")
+	}
+}
+
+func TestRecoverPanicWithFiberErrorStillReportedAsPanic(t *testing.T) {
+	logger, logs := logtest.NewObserver("info")
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandlerWithConfig(ErrorHandlerConfig{Logger: logger})})
+	app.Use(Recover())
+	app.Get("/test", func(c *fiber.Ctx) error { panic(fiber.ErrBadRequest) })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+	if n := logs.FilterMessage("panic recovered").Len(); n != 1 {
+		t.Fatalf("expected panic to be logged once, got %d", n)
+	}
+}
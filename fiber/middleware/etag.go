@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETagConfig defines configuration for the ETag middleware.
+type ETagConfig struct {
+	// Weak emits weak validators (W/"...") instead of strong ones (default: false)
+	Weak bool
+}
+
+// ETag returns a middleware that sets a strong ETag from the response body and
+// answers matching If-None-Match requests with 304 Not Modified.
+//
+// Example usage:
+//
+//	app.Use(middleware.ETag())
+func ETag() fiber.Handler {
+	return ETagWithConfig(ETagConfig{})
+}
+
+// ETagWithConfig returns an ETag middleware with custom configuration.
+//
+// Behaviour:
+// - Only GET and HEAD requests are handled
+// - Non-2xx responses and streamed bodies are passed through untouched
+// - An ETag already set by the handler is kept and still used for matching
+//
+// Example usage:
+//
+//	app.Use(middleware.ETagWithConfig(middleware.ETagConfig{Weak: true}))
+func ETagWithConfig(cfg ETagConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		resp := c.Response()
+		status := resp.StatusCode()
+		if status < 200 || status >= 300 || resp.IsBodyStream() {
+			return nil
+		}
+
+		etag := string(resp.Header.Peek(fiber.HeaderETag))
+		if etag == "" {
+			etag = computeETag(resp.Body(), cfg.Weak)
+			c.Set(fiber.HeaderETag, etag)
+		}
+
+		if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+			resp.ResetBody()
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+
+		return nil
+	}
+}
+
+// computeETag builds a validator from the body length and its FNV-1a hash.
+func computeETag(body []byte, weak bool) string {
+	h := fnv.New64a()
+	_, _ = h.Write(body)
+
+	tag := `"` + strconv.Itoa(len(body)) + "-" + strconv.FormatUint(h.Sum64(), 16) + `"`
+	if weak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using weak
+// comparison, as required for If-None-Match (RFC 9110 section 13.1.2).
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == target {
+			return true
+		}
+	}
+	return false
+}
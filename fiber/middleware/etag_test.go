@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newETagApp(cfg ETagConfig) *fiber.App {
+	app := fiber.New()
+	app.Use(ETagWithConfig(cfg))
+	app.Get("/data", func(c *fiber.Ctx) error { return c.JSON(fiber.Map{"id": 1}) })
+	app.Get("/missing", func(c *fiber.Ctx) error { return c.Status(fiber.StatusNotFound).SendString("nope") })
+	return app
+}
+
+func TestETagSetsHeaderAndReturns304(t *testing.T) {
+	app := newETagApp(ETagConfig{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/data", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	etag := resp.Header.Get(fiber.HeaderETag)
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		t.Fatalf("expected strong ETag, got %q", etag)
+	}
+
+	req := httptest.NewRequest("GET", "/data", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotModified {
+		t.Fatalf("expected 304, got %d", resp.StatusCode)
+	}
+}
+
+func TestETagWeak(t *testing.T) {
+	app := newETagApp(ETagConfig{Weak: true})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/data", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if etag := resp.Header.Get(fiber.HeaderETag); !strings.HasPrefix(etag, "W/") {
+		t.Fatalf("expected weak ETag, got %q", etag)
+	}
+}
+
+func TestETagSkipsNon2xx(t *testing.T) {
+	app := newETagApp(ETagConfig{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/missing", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.Header.Get(fiber.HeaderETag) != "" {
+		t.Fatal("expected no ETag for 404 response")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	if !etagMatches(`"a", W/"b"`, `"b"`) {
+		t.Fatal("expected weak comparison to match")
+	}
+	if !etagMatches("*", `"x"`) {
+		t.Fatal("expected wildcard to match")
+	}
+	if etagMatches(`"a"`, `"b"`) {
+		t.Fatal("expected mismatch")
+	}
+}
@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultIdempotencyHeader  = "Idempotency-Key"
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = time.Minute
+	maxIdempotencyKeyLength   = 255
+
+	// HeaderIdempotentReplayed is set to "true" on responses replayed from the store.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// IdempotentResponse is a captured response replayed for retries with the same key.
+type IdempotentResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+	Fingerprint string `json:"fingerprint"` // Hash of the request body, to detect key reuse
+}
+
+// IdempotencyStore persists idempotency reservations and captured responses.
+// Implementations must make Acquire atomic per key (e.g. Redis SET NX with an expiry),
+// so only one of several concurrent requests with the same key proceeds.
+type IdempotencyStore interface {
+	// Acquire reserves key for an in-flight request for at most lockTTL. If a response
+	// was already stored for key it is returned instead. acquired is false when
+	// another request holds the reservation.
+	Acquire(ctx context.Context, key string, lockTTL time.Duration) (resp *IdempotentResponse, acquired bool, err error)
+
+	// Complete stores resp for key for ttl, replacing the reservation.
+	Complete(ctx context.Context, key string, resp IdempotentResponse, ttl time.Duration) error
+
+	// Release drops the reservation for key without storing a response, so it can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig defines configuration for the Idempotency middleware.
+type IdempotencyConfig struct {
+	// Store persists reservations and responses (default: a new in-memory store)
+	// The in-memory store is per process; use a shared store (e.g. Redis) when running
+	// several instances.
+	Store IdempotencyStore
+
+	// Header carries the client's idempotency key (default: "Idempotency-Key")
+	Header string
+
+	// TTL is how long a captured response is replayed for its key (default: 24h)
+	TTL time.Duration
+
+	// LockTTL bounds how long an in-flight request holds its key, so a crashed
+	// instance doesn't block retries forever (default: 1m)
+	LockTTL time.Duration
+
+	// Methods are the HTTP methods idempotency applies to (default: POST, PATCH)
+	Methods []string
+
+	// Scope namespaces keys so different clients can't replay each other's responses
+	// Default: the tenant and user from c.UserContext() (contextx.TenantID, contextx.UserID)
+	// The default needs authentication middleware that sets them; without it every
+	// client shares one key space, so another client reusing a guessable key would be
+	// replayed the first client's response. Set Scope (e.g. to an API key or session ID)
+	// on routes that aren't authenticated.
+	Scope func(c *fiber.Ctx) string
+}
+
+// Idempotency returns a middleware that makes retries of non-idempotent requests safe.
+// When a request carries an Idempotency-Key, the first response for that key is
+// captured and replayed (status, Content-Type, and body, with Idempotent-Replayed: true)
+// for later requests with the same key, without running the handler again.
+//
+// Behaviour:
+// - Requests without the header, or with other methods, pass through untouched
+// - Keys are scoped by Scope (default: tenant and user), method, and path; keys over 255 characters are rejected with 400
+// - Unauthenticated routes need an explicit Scope; see IdempotencyConfig.Scope
+// - A retry while the original is still in flight gets 409 Conflict
+// - Reusing a key with a different request body gets 422 Unprocessable Entity
+// - Handler errors, 5xx responses, and streamed bodies are not stored, so the client may retry
+//
+// Example usage:
+//
+//	payments := app.Group("/payments", middleware.Idempotency(middleware.IdempotencyConfig{
+//	    Store: redisIdempotencyStore, // implements middleware.IdempotencyStore
+//	    TTL:   24 * time.Hour,
+//	}))
+//	payments.Post("/", createPayment)
+func Idempotency(cfg IdempotencyConfig) fiber.Handler {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryIdempotencyStore()
+	}
+	if cfg.Header == "" {
+		cfg.Header = defaultIdempotencyHeader
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultIdempotencyTTL
+	}
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = defaultIdempotencyLockTTL
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = []string{fiber.MethodPost, fiber.MethodPatch}
+	}
+	if cfg.Scope == nil {
+		cfg.Scope = defaultIdempotencyScope
+	}
+
+	methods := make(map[string]bool, len(cfg.Methods))
+	for _, m := range cfg.Methods {
+		methods[strings.ToUpper(m)] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		idemKey := c.Get(cfg.Header)
+		if idemKey == "" || !methods[c.Method()] {
+			return c.Next()
+		}
+		if len(idemKey) > maxIdempotencyKeyLength {
+			return fiber.NewError(fiber.StatusBadRequest, cfg.Header+" is too long")
+		}
+
+		// Build a fresh string: header values alias reused Fiber buffers
+		key := strings.Join([]string{cfg.Scope(c), c.Method(), c.Path(), idemKey}, "|")
+		fingerprint := requestFingerprint(c.Body())
+		ctx := c.UserContext()
+
+		stored, acquired, err := cfg.Store.Acquire(ctx, key, cfg.LockTTL)
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			if stored.Fingerprint != fingerprint {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, cfg.Header+" was already used for a different request")
+			}
+			if stored.ContentType != "" {
+				c.Set(fiber.HeaderContentType, stored.ContentType)
+			}
+			c.Set(HeaderIdempotentReplayed, "true")
+			return c.Status(stored.Status).Send(stored.Body)
+		}
+		if !acquired {
+			return fiber.NewError(fiber.StatusConflict, "a request with this "+cfg.Header+" is already in progress")
+		}
+
+		if err := c.Next(); err != nil {
+			_ = cfg.Store.Release(ctx, key)
+			return err
+		}
+
+		resp := c.Response()
+		if resp.StatusCode() >= fiber.StatusInternalServerError || resp.IsBodyStream() {
+			return cfg.Store.Release(ctx, key)
+		}
+
+		return cfg.Store.Complete(ctx, key, IdempotentResponse{
+			Status:      resp.StatusCode(),
+			ContentType: string(resp.Header.ContentType()),
+			Body:        append([]byte(nil), resp.Body()...),
+			Fingerprint: fingerprint,
+		}, cfg.TTL)
+	}
+}
+
+// defaultIdempotencyScope scopes keys by tenant and user, joined with a NUL byte so
+// distinct pairs can't collide. Returns "" when neither is set.
+func defaultIdempotencyScope(c *fiber.Ctx) string {
+	ctx := c.UserContext()
+	tenantID, _ := contextx.TenantID(ctx)
+	userID, _ := contextx.UserID(ctx)
+	if tenantID == "" && userID == "" {
+		return ""
+	}
+	return tenantID + "\x00" + userID
+}
+
+// requestFingerprint returns a hex SHA-256 of the request body.
+func requestFingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore. Expired entries are
+// removed lazily during Acquire. It is safe for concurrent use.
+type MemoryIdempotencyStore struct {
+	mu          sync.Mutex
+	entries     map[string]memoryIdempotencyEntry
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+// memoryIdempotencyEntry is a reservation (resp == nil) or a stored response.
+type memoryIdempotencyEntry struct {
+	resp    *IdempotentResponse
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory store.
+//
+// Example usage:
+//
+//	store := middleware.NewMemoryIdempotencyStore()
+//	app.Use(middleware.Idempotency(middleware.IdempotencyConfig{Store: store}))
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries:     make(map[string]memoryIdempotencyEntry),
+		lastCleanup: time.Now(),
+		now:         time.Now,
+	}
+}
+
+// Acquire implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Acquire(_ context.Context, key string, lockTTL time.Duration) (*IdempotentResponse, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastCleanup) > bucketCleanupInterval {
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastCleanup = now
+	}
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		if e.resp != nil {
+			return e.resp, false, nil
+		}
+		return nil, false, nil
+	}
+
+	s.entries[key] = memoryIdempotencyEntry{expires: now.Add(lockTTL)}
+	return nil, true, nil
+}
+
+// Complete implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, resp IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryIdempotencyEntry{resp: &resp, expires: s.now().Add(ttl)}
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.resp == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
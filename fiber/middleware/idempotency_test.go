@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/gofiber/fiber/v2"
+)
+
+// idempotentRequest sends a POST with an Idempotency-Key and returns status and body.
+func idempotentRequest(t *testing.T, app *fiber.App, key, body string) (int, string, string) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/pay", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", key)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data), resp.Header.Get(HeaderIdempotentReplayed)
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Use(Idempotency(IdempotencyConfig{}))
+	app.Post("/pay", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"payment": calls})
+	})
+
+	status, body, replayed := idempotentRequest(t, app, "k1", `{"amount":10}`)
+	if status != fiber.StatusCreated || replayed != "" {
+		t.Fatalf("unexpected first response: %d %q replayed=%q", status, body, replayed)
+	}
+
+	status2, body2, replayed2 := idempotentRequest(t, app, "k1", `{"amount":10}`)
+	if status2 != status || body2 != body || replayed2 != "true" {
+		t.Fatalf("expected replay of %d %q, got %d %q replayed=%q", status, body, status2, body2, replayed2)
+	}
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+
+	// A different key runs the handler again
+	if _, body3, _ := idempotentRequest(t, app, "k2", `{"amount":10}`); body3 == body {
+		t.Fatal("expected a new response for a different key")
+	}
+}
+
+func TestIdempotencyRejectsInFlightAndReusedKeys(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	app := fiber.New()
+	app.Use(Idempotency(IdempotencyConfig{Store: store}))
+	app.Post("/pay", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	// Simulate a request holding the key
+	if _, acquired, _ := store.Acquire(context.Background(), "|POST|/pay|busy", time.Minute); !acquired {
+		t.Fatal("expected to acquire key")
+	}
+	if status, _, _ := idempotentRequest(t, app, "busy", "{}"); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for in-flight key, got %d", status)
+	}
+
+	idempotentRequest(t, app, "k1", `{"amount":10}`)
+	if status, _, _ := idempotentRequest(t, app, "k1", `{"amount":99}`); status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for reused key, got %d", status)
+	}
+}
+
+func TestIdempotencyDoesNotStoreFailures(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Use(Idempotency(IdempotencyConfig{}))
+	app.Post("/pay", func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			return c.SendStatus(fiber.StatusServiceUnavailable)
+		}
+		return c.SendString("ok")
+	})
+
+	idempotentRequest(t, app, "k1", "{}")
+	if status, body, _ := idempotentRequest(t, app, "k1", "{}"); status != fiber.StatusOK || body != "ok" {
+		t.Fatalf("expected retry after 5xx to run the handler, got %d %q", status, body)
+	}
+}
+
+func TestMemoryIdempotencyStoreExpiry(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := store.Complete(ctx, "k", IdempotentResponse{Status: 201}, time.Hour); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if resp, _, _ := store.Acquire(ctx, "k", time.Minute); resp == nil || resp.Status != 201 {
+		t.Fatalf("expected stored response, got %+v", resp)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if resp, acquired, _ := store.Acquire(ctx, "k", time.Minute); resp != nil || !acquired {
+		t.Fatalf("expected expired response to be gone, got %+v acquired=%v", resp, acquired)
+	}
+}
+
+func TestIdempotencyDefaultScopeSeparatesUsers(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		ctx := contextx.WithTenant(c.UserContext(), "t1")
+		c.SetUserContext(contextx.WithUser(ctx, c.Get("X-User")))
+		return c.Next()
+	})
+	app.Use(Idempotency(IdempotencyConfig{}))
+	app.Post("/pay", func(c *fiber.Ctx) error {
+		calls++
+		return c.SendString(c.Get("X-User"))
+	})
+
+	do := func(user string) (string, string) {
+		req := httptest.NewRequest("POST", "/pay", strings.NewReader("{}"))
+		req.Header.Set("Idempotency-Key", "order-1")
+		req.Header.Set("X-User", user)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		return string(data), resp.Header.Get(HeaderIdempotentReplayed)
+	}
+
+	do("alice")
+	if body, replayed := do("bob"); body != "bob" || replayed != "" {
+		t.Fatalf("expected bob's own response, got %q replayed=%q", body, replayed)
+	}
+	if body, replayed := do("alice"); body != "alice" || replayed != "true" {
+		t.Fatalf("expected alice's replay, got %q replayed=%q", body, replayed)
+	}
+	if calls != 2 {
+		t.Fatalf("expected one handler run per user, got %d", calls)
+	}
+}
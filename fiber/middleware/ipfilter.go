@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IPFilterConfig defines configuration for the IP filter middleware.
+// Entries may be CIDRs ("10.0.0.0/8") or single addresses ("203.0.113.7").
+type IPFilterConfig struct {
+	// Allow lists permitted client networks (default: empty = allow all except denied)
+	Allow []string
+
+	// Deny lists blocked client networks; takes precedence over Allow
+	Deny []string
+
+	// TrustedProxies lists proxy networks whose forwarding headers are honoured.
+	// Behind a trusted peer, X-Forwarded-For is walked from right to left, skipping
+	// trusted addresses, and the first untrusted one is the client; entries a client
+	// prepends itself are never reached. Requests from other peers are filtered by
+	// their direct remote address (default: none)
+	TrustedProxies []string
+
+	// ClientIPHeader names a header that a trusted proxy always sets to the client
+	// address, overwriting any client-supplied value, e.g. "CF-Connecting-IP" behind
+	// Cloudflare or "X-Real-IP" behind nginx with proxy_set_header. It is read only
+	// from trusted peers, before X-Forwarded-For. Only opt in when the proxy really
+	// overwrites it; otherwise clients can spoof it (default: "" = X-Forwarded-For only)
+	ClientIPHeader string
+}
+
+// IPFilter returns a middleware that allows or denies requests by client IP.
+// CIDRs are parsed once at construction; invalid entries cause a panic so
+// misconfiguration fails at startup. Denied requests receive 403 Forbidden.
+//
+// Example usage:
+//
+//	admin := app.Group("/admin", middleware.IPFilter(middleware.IPFilterConfig{
+//	    Allow:          []string{"198.51.100.0/24"}, // office network
+//	    TrustedProxies: []string{"10.0.0.0/8"},      // load balancers
+//	}))
+func IPFilter(cfg IPFilterConfig) fiber.Handler {
+	allow := mustParsePrefixes("allow", cfg.Allow)
+	deny := mustParsePrefixes("deny", cfg.Deny)
+	trusted := mustParsePrefixes("trusted proxy", cfg.TrustedProxies)
+
+	return func(c *fiber.Ctx) error {
+		ip, ok := resolveClientIP(c, trusted, cfg.ClientIPHeader)
+		if !ok {
+			return fiber.ErrForbidden
+		}
+
+		if containsIP(deny, ip) {
+			return fiber.ErrForbidden
+		}
+		if len(allow) > 0 && !containsIP(allow, ip) {
+			return fiber.ErrForbidden
+		}
+
+		return c.Next()
+	}
+}
+
+// resolveClientIP returns the client address, honouring proxy headers only when the
+// direct peer is a trusted proxy. Malformed forwarded addresses are rejected.
+func resolveClientIP(c *fiber.Ctx, trusted []netip.Prefix, header string) (netip.Addr, bool) {
+	remote, ok := netip.AddrFromSlice(c.Context().RemoteIP())
+	if !ok {
+		return netip.Addr{}, false
+	}
+	remote = remote.Unmap()
+
+	if !containsIP(trusted, remote) {
+		return remote, true
+	}
+
+	if header != "" {
+		if v := strings.TrimSpace(c.Get(header)); v != "" {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return netip.Addr{}, false
+			}
+			return addr.Unmap(), true
+		}
+	}
+
+	// Each proxy appends the address it received from, so the rightmost untrusted
+	// entry is the closest hop we can vouch for
+	var hops []string
+	for _, v := range c.Request().Header.PeekAll(fiber.HeaderXForwardedFor) {
+		hops = append(hops, strings.Split(string(v), ",")...)
+	}
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		client = addr.Unmap()
+		if !containsIP(trusted, client) {
+			break
+		}
+	}
+	return client, true
+}
+
+// containsIP reports whether any prefix contains ip.
+func containsIP(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// mustParsePrefixes parses CIDRs or single addresses, panicking on invalid input.
+func mustParsePrefixes(kind string, entries []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				panic(fmt.Sprintf("invalid %s CIDR %q: %v", kind, e, err))
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(e)
+		if err != nil {
+			panic(fmt.Sprintf("invalid %s address %q: %v", kind, e, err))
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// app.Test connects from 0.0.0.0, which is used as the direct peer address below.
+func testIPFilter(t *testing.T, cfg IPFilterConfig, headers map[string]string) int {
+	t.Helper()
+
+	app := fiber.New()
+	app.Use(IPFilter(cfg))
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func TestIPFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     IPFilterConfig
+		headers map[string]string
+		want    int
+	}{
+		{"empty config allows all", IPFilterConfig{}, nil, fiber.StatusOK},
+		{"allow matches peer", IPFilterConfig{Allow: []string{"0.0.0.0/8"}}, nil, fiber.StatusOK},
+		{"allow excludes peer", IPFilterConfig{Allow: []string{"10.0.0.0/8"}}, nil, fiber.StatusForbidden},
+		{"deny wins over allow", IPFilterConfig{Allow: []string{"0.0.0.0/0"}, Deny: []string{"0.0.0.0"}}, nil, fiber.StatusForbidden},
+		{
+			"untrusted peer headers ignored",
+			IPFilterConfig{Allow: []string{"198.51.100.0/24"}},
+			map[string]string{"X-Real-IP": "198.51.100.7"},
+			fiber.StatusForbidden,
+		},
+		{
+			"trusted proxy forwarded allow",
+			IPFilterConfig{Allow: []string{"198.51.100.0/24"}, TrustedProxies: []string{"0.0.0.0/8"}},
+			map[string]string{"X-Forwarded-For": "198.51.100.7"},
+			fiber.StatusOK,
+		},
+		{
+			"trusted proxy forwarded deny",
+			IPFilterConfig{Deny: []string{"203.0.113.0/24"}, TrustedProxies: []string{"0.0.0.0/8", "10.0.0.0/8"}},
+			map[string]string{"X-Forwarded-For": "203.0.113.9, 10.0.0.1"},
+			fiber.StatusForbidden,
+		},
+		{
+			"spoofed leftmost forwarded entry ignored",
+			IPFilterConfig{Allow: []string{"198.51.100.0/24"}, TrustedProxies: []string{"0.0.0.0/8"}},
+			map[string]string{"X-Forwarded-For": "198.51.100.9, 203.0.113.50"},
+			fiber.StatusForbidden,
+		},
+		{
+			"malformed forwarded entry rejected",
+			IPFilterConfig{TrustedProxies: []string{"0.0.0.0/8"}},
+			map[string]string{"X-Forwarded-For": "not-an-ip"},
+			fiber.StatusForbidden,
+		},
+		{
+			"client headers ignored without opt-in",
+			IPFilterConfig{Allow: []string{"198.51.100.0/24"}, TrustedProxies: []string{"0.0.0.0/8"}},
+			map[string]string{"X-Real-IP": "198.51.100.7", "CF-Connecting-IP": "198.51.100.7"},
+			fiber.StatusForbidden,
+		},
+		{
+			"opted-in client IP header honoured",
+			IPFilterConfig{Allow: []string{"198.51.100.0/24"}, TrustedProxies: []string{"0.0.0.0/8"}, ClientIPHeader: "CF-Connecting-IP"},
+			map[string]string{"CF-Connecting-IP": "198.51.100.7", "X-Forwarded-For": "203.0.113.50"},
+			fiber.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testIPFilter(t, tt.cfg, tt.headers); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIPFilterInvalidCIDRPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected invalid CIDR to panic")
+		}
+	}()
+	IPFilter(IPFilterConfig{Allow: []string{"10.0.0.0/99"}})
+}
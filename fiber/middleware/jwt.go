@@ -0,0 +1,269 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultJWTClaimsLocal is the c.Locals key holding verified claims.
+const defaultJWTClaimsLocal = "jwt_claims"
+
+// JWTHeader is the decoded JOSE header of a token, passed to JWTConfig.KeyFunc.
+type JWTHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// JWTConfig defines configuration for the JWT middleware.
+type JWTConfig struct {
+	// KeyFunc returns the key verifying a token with the given header (required):
+	// a []byte secret for HS256/HS384/HS512 or an *rsa.PublicKey for RS256/RS384/RS512.
+	// A key of the wrong type for header.Alg is rejected, so an RSA public key can never
+	// be used as an HMAC secret. Use header.Kid to pick among rotated keys.
+	KeyFunc func(header JWTHeader) (interface{}, error)
+
+	// Claims returns a new pointer the payload is decoded into, e.g. func() any { return &MyClaims{} }
+	// Default: nil = decode into map[string]interface{}
+	Claims func() interface{}
+
+	// ContextSetter is called with the decoded claims after verification, to store
+	// tenant, user, and similar values in c.UserContext() with the contextx setters
+	// (default: nil). A returned error is passed on instead of calling the next handler.
+	ContextSetter func(c *fiber.Ctx, claims interface{}) error
+
+	// Header is the request header carrying "Bearer <token>" (default: "Authorization")
+	Header string
+
+	// TokenLookup lists token sources tried in order, comma separated (default: "header")
+	// - "header" uses Header; "header:<name>" uses another header (both expect "Bearer <token>")
+	// - "cookie:<name>" reads the raw token from a cookie
+	// Example: "header,cookie:access_token"
+	TokenLookup string
+
+	// ClaimsLocal is the c.Locals key the decoded claims are stored under (default: "jwt_claims")
+	ClaimsLocal string
+
+	// Leeway tolerates clock skew when checking exp and nbf (default: 0)
+	Leeway time.Duration
+}
+
+// jwtAlgorithms maps supported "alg" values to their hash.
+var jwtAlgorithms = map[string]crypto.Hash{
+	"HS256": crypto.SHA256,
+	"HS384": crypto.SHA384,
+	"HS512": crypto.SHA512,
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// jwtRegisteredClaims holds the time claims checked by the middleware.
+type jwtRegisteredClaims struct {
+	Exp *json.Number `json:"exp"`
+	Nbf *json.Number `json:"nbf"`
+}
+
+// JWT returns a middleware that authenticates requests with a JSON Web Token.
+// It verifies the signature (HS256/384/512 or RS256/384/512) and the exp and nbf
+// claims, stores the decoded claims in c.Locals(ClaimsLocal), and calls ContextSetter
+// so they flow into contextx. Missing, malformed, or invalid tokens get a 401 through
+// the ErrorHandler's standard envelope; details are not exposed to the client.
+//
+// Panics if KeyFunc is nil or TokenLookup has an unknown source.
+//
+// Example usage:
+//
+//	type Claims struct {
+//	    Subject  string   `json:"sub"`
+//	    TenantID string   `json:"tenant_id"`
+//	    Roles    []string `json:"roles"`
+//	}
+//
+//	api := app.Group("/api", middleware.JWT(middleware.JWTConfig{
+//	    KeyFunc: func(h middleware.JWTHeader) (interface{}, error) {
+//	        return []byte(cfg.GetString("auth.jwt_secret")), nil
+//	    },
+//	    Claims:      func() interface{} { return &Claims{} },
+//	    TokenLookup: "header,cookie:access_token",
+//	    ContextSetter: func(c *fiber.Ctx, claims interface{}) error {
+//	        cl := claims.(*Claims)
+//	        ctx := contextx.WithTenant(c.UserContext(), cl.TenantID)
+//	        c.SetUserContext(contextx.WithUser(ctx, cl.Subject))
+//	        return nil
+//	    },
+//	}))
+func JWT(cfg JWTConfig) fiber.Handler {
+	if cfg.KeyFunc == nil {
+		panic("jwt: KeyFunc is required")
+	}
+	if cfg.Header == "" {
+		cfg.Header = fiber.HeaderAuthorization
+	}
+	if cfg.TokenLookup == "" {
+		cfg.TokenLookup = "header"
+	}
+	if cfg.ClaimsLocal == "" {
+		cfg.ClaimsLocal = defaultJWTClaimsLocal
+	}
+	extractors := jwtExtractors(cfg.TokenLookup, cfg.Header)
+
+	return func(c *fiber.Ctx) error {
+		token := ""
+		for _, extract := range extractors {
+			if token = extract(c); token != "" {
+				break
+			}
+		}
+		if token == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "missing or malformed token")
+		}
+
+		claims, err := verifyJWT(token, cfg)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid or expired token")
+		}
+
+		c.Locals(cfg.ClaimsLocal, claims)
+		if cfg.ContextSetter != nil {
+			if err := cfg.ContextSetter(c, claims); err != nil {
+				return err
+			}
+		}
+		return c.Next()
+	}
+}
+
+// jwtExtractors parses TokenLookup into token extractors. Panics on unknown sources.
+func jwtExtractors(lookup, defaultHeader string) []func(c *fiber.Ctx) string {
+	var extractors []func(c *fiber.Ctx) string
+	for _, source := range strings.Split(lookup, ",") {
+		kind, name, _ := strings.Cut(strings.TrimSpace(source), ":")
+		switch {
+		case kind == "header":
+			if name == "" {
+				name = defaultHeader
+			}
+			extractors = append(extractors, func(c *fiber.Ctx) string {
+				scheme, token, ok := strings.Cut(c.Get(name), " ")
+				if !ok || !strings.EqualFold(scheme, "Bearer") {
+					return ""
+				}
+				return strings.TrimSpace(token)
+			})
+		case kind == "cookie" && name != "":
+			extractors = append(extractors, func(c *fiber.Ctx) string {
+				return c.Cookies(name)
+			})
+		default:
+			panic(fmt.Sprintf("jwt: unknown TokenLookup source %q", source))
+		}
+	}
+	return extractors
+}
+
+// verifyJWT checks the token's signature and time claims and returns its decoded claims.
+func verifyJWT(token string, cfg JWTConfig) (interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token must have three parts")
+	}
+
+	var header JWTHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	hash, ok := jwtAlgorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+
+	key, err := cfg.KeyFunc(header)
+	if err != nil {
+		return nil, fmt.Errorf("key: %w", err)
+	}
+	if err := verifyJWTSignature(header.Alg, hash, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var registered jwtRegisteredClaims
+	if err := decodeJWTPart(parts[1], &registered); err != nil {
+		return nil, fmt.Errorf("claims: %w", err)
+	}
+	now := time.Now()
+	if registered.Exp != nil && !now.Before(jwtTime(*registered.Exp).Add(cfg.Leeway)) {
+		return nil, errors.New("token expired")
+	}
+	if registered.Nbf != nil && now.Add(cfg.Leeway).Before(jwtTime(*registered.Nbf)) {
+		return nil, errors.New("token not valid yet")
+	}
+
+	if cfg.Claims == nil {
+		var claims map[string]interface{}
+		if err := decodeJWTPart(parts[1], &claims); err != nil {
+			return nil, fmt.Errorf("claims: %w", err)
+		}
+		return claims, nil
+	}
+	claims := cfg.Claims()
+	if err := decodeJWTPart(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("claims: %w", err)
+	}
+	return claims, nil
+}
+
+// verifyJWTSignature checks sig over signed with key, which must match alg's family.
+func verifyJWTSignature(alg string, hash crypto.Hash, key interface{}, signed string, sig []byte) error {
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return fmt.Errorf("%s requires a non-empty []byte key, got %T", alg, key)
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	default: // RS
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || pub == nil {
+			return fmt.Errorf("%s requires an *rsa.PublicKey, got %T", alg, key)
+		}
+		h := hash.New()
+		h.Write([]byte(signed))
+		return rsa.VerifyPKCS1v15(pub, hash, h.Sum(nil), sig)
+	}
+}
+
+// decodeJWTPart base64url-decodes a token segment and unmarshals its JSON into v.
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jwtTime converts a NumericDate (seconds since the epoch, possibly fractional) to a time.
+func jwtTime(n json.Number) time.Time {
+	if secs, err := n.Int64(); err == nil {
+		return time.Unix(secs, 0)
+	}
+	f, _ := n.Float64()
+	return time.Unix(0, int64(f*float64(time.Second)))
+}
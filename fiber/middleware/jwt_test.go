@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/gofiber/fiber/v2"
+)
+
+// signTestJWT builds a compact JWT for claims, signed with an HMAC secret ([]byte)
+// for HS256 or an *rsa.PrivateKey for RS256.
+func signTestJWT(t *testing.T, alg string, key interface{}, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		sum := sha256.Sum256([]byte(signed))
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, sum[:]); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func newJWTTestApp(cfg JWTConfig) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Use(JWT(cfg))
+	app.Get("/", func(c *fiber.Ctx) error {
+		tenantID, _ := contextx.TenantID(c.UserContext())
+		return c.SendString(tenantID)
+	})
+	return app
+}
+
+func TestJWTHS256(t *testing.T) {
+	secret := []byte("s3cret")
+	app := newJWTTestApp(JWTConfig{
+		KeyFunc: func(JWTHeader) (interface{}, error) { return secret, nil },
+		ContextSetter: func(c *fiber.Ctx, claims interface{}) error {
+			tenantID, _ := claims.(map[string]interface{})["tenant_id"].(string)
+			c.SetUserContext(contextx.WithTenant(c.UserContext(), tenantID))
+			return nil
+		},
+	})
+
+	valid := signTestJWT(t, "HS256", secret, map[string]interface{}{
+		"tenant_id": "acme",
+		"exp":       time.Now().Add(time.Minute).Unix(),
+	})
+	expired := signTestJWT(t, "HS256", secret, map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})
+	forged := signTestJWT(t, "HS256", []byte("other"), map[string]interface{}{"tenant_id": "acme"})
+
+	cases := []struct {
+		name   string
+		auth   string
+		status int
+	}{
+		{"valid", "Bearer " + valid, fiber.StatusOK},
+		{"missing", "", fiber.StatusUnauthorized},
+		{"wrong scheme", "Basic " + valid, fiber.StatusUnauthorized},
+		{"expired", "Bearer " + expired, fiber.StatusUnauthorized},
+		{"bad signature", "Bearer " + forged, fiber.StatusUnauthorized},
+		{"malformed", "Bearer not.a-token", fiber.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: app test: %v", tc.name, err)
+		}
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestJWTRS256FromCookie(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	type claims struct {
+		TenantID string `json:"tenant_id"`
+	}
+	app := newJWTTestApp(JWTConfig{
+		KeyFunc:     func(JWTHeader) (interface{}, error) { return &priv.PublicKey, nil },
+		Claims:      func() interface{} { return &claims{} },
+		TokenLookup: "header,cookie:access_token",
+		ContextSetter: func(c *fiber.Ctx, cl interface{}) error {
+			c.SetUserContext(contextx.WithTenant(c.UserContext(), cl.(*claims).TenantID))
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Cookie", "access_token="+signTestJWT(t, "RS256", priv, map[string]interface{}{"tenant_id": "acme"}))
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	// An HS256 token "signed" with the public key must not verify against it
+	pubBytes := priv.PublicKey.N.Bytes()
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestJWT(t, "HS256", pubBytes, map[string]interface{}{"tenant_id": "acme"}))
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401 for algorithm confusion, got %d", resp.StatusCode)
+	}
+}
+
+func TestJWTPanicsOnMisconfiguration(t *testing.T) {
+	for name, cfg := range map[string]JWTConfig{
+		"no key func":    {},
+		"unknown lookup": {KeyFunc: func(JWTHeader) (interface{}, error) { return nil, nil }, TokenLookup: "query:token"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expected panic", name)
+				}
+			}()
+			JWT(cfg)
+		}()
+	}
+}
@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cubetiqlabs/gopkg/contextx"
@@ -9,45 +12,229 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// UnmatchedRoutes controls how the metrics middleware records requests that
+// matched no route handler (Fiber's "Cannot GET /path" 404 or 405 responses).
+type UnmatchedRoutes int
+
+const (
+	// UnmatchedRecord records unmatched requests like any other (default).
+	UnmatchedRecord UnmatchedRoutes = iota
+	// UnmatchedSkip records nothing for unmatched requests.
+	UnmatchedSkip
+	// UnmatchedLabel counts unmatched requests under path="not_found" and leaves them
+	// out of RequestDuration and size histograms, so scanners don't skew latency.
+	UnmatchedLabel
+)
+
+// notFoundPathLabel is the path label used for unmatched requests in UnmatchedLabel mode.
+const notFoundPathLabel = "not_found"
+
+// StatusClientClosedRequest is the non-standard status (nginx's 499) recorded for
+// requests whose client went away before a response was produced.
+const StatusClientClosedRequest = 499
+
+// MetricsConfig defines configuration for the metrics middleware.
+type MetricsConfig struct {
+	// RecordSizes observes request and response body sizes into the
+	// http_request_bytes and http_response_bytes labeled histograms (default: false)
+	//
+	// Request size uses Content-Length when present, otherwise the buffered body length.
+	// Streamed responses (SetBodyStream) are only recorded when their size is known
+	// up front via Content-Length; chunked streams are skipped so the body is never
+	// buffered just to measure it.
+	RecordSizes bool
+
+	// LatencyBuckets are the bucket upper bounds in milliseconds for the
+	// http_request_duration_ms histogram (default: metrics.DefaultLatencyBucketsMs)
+	// They are set on reg.RequestDuration when the middleware is created, unless it
+	// already has buckets (e.g. from another Metrics middleware on the same registry)
+	// or observations; the registry's histogram is then left as is.
+	LatencyBuckets []float64
+
+	// SizeBuckets are the bucket upper bounds in bytes for the size histograms
+	// recorded with RecordSizes (default: metrics.DefaultSizeBucketsBytes)
+	// Like LatencyBuckets, they are ignored if the histograms already have buckets or observations.
+	SizeBuckets []float64
+
+	// Unmatched controls recording of requests that matched no route handler (default: UnmatchedRecord)
+	// Only Fiber's own not-found errors are detected; handlers that return 404 are still recorded normally.
+	Unmatched UnmatchedRoutes
+}
+
 // Metrics returns a Fiber middleware that collects request metrics.
 // It tracks:
 // - Total requests
-// - Request duration (avg, sum, count)
+// - In-flight requests (http_inflight_requests gauge)
+// - Request duration (avg, sum, count, and latency buckets)
 // - Labeled metrics by method, path, status, and optionally tenant
 //
+// Client-cancelled requests are counted under status="499" and left out of the
+// duration and size histograms. Fasthttp doesn't signal client disconnects on its
+// own, so a request counts as cancelled when the handler returns an error wrapping
+// context.Canceled, or when c.UserContext() reports context.Canceled after the handler
+// chain. Wire a cancellable UserContext for this to take effect. c.Context() is not
+// consulted: fasthttp only cancels it on server shutdown, not on client disconnect.
+//
 // Example usage:
 //
 //	reg := metrics.NewRegistry()
 //	app.Use(middleware.Metrics(reg))
 //
 //	// Expose metrics endpoint
-//	app.Get("/metrics", func(c *fiber.Ctx) error {
-//	    c.Set("Content-Type", "text/plain")
-//	    return c.SendString(reg.RenderPrometheus())
-//	})
+//	app.Get("/metrics", middleware.MetricsHandler(reg))
 func Metrics(reg *metrics.Registry) fiber.Handler {
+	return MetricsWithConfig(reg, MetricsConfig{})
+}
+
+// MetricsWithConfig returns a metrics middleware with custom configuration.
+//
+// Example usage:
+//
+//	reg := metrics.NewRegistry()
+//	app.Use(middleware.MetricsWithConfig(reg, middleware.MetricsConfig{
+//	    RecordSizes: true,
+//	    Unmatched:   middleware.UnmatchedLabel, // Keep scanner traffic out of latency
+//	}))
+func MetricsWithConfig(reg *metrics.Registry, cfg MetricsConfig) fiber.Handler {
+	// Set defaults
+	if cfg.LatencyBuckets == nil {
+		cfg.LatencyBuckets = metrics.DefaultLatencyBucketsMs
+	}
+	if cfg.SizeBuckets == nil {
+		cfg.SizeBuckets = metrics.DefaultSizeBucketsBytes
+	}
+
+	// Best effort: a registry shared with another configuration keeps its buckets
+	reg.RequestDuration.SetBuckets(cfg.LatencyBuckets)
+	if cfg.RecordSizes {
+		reg.SetHistogramBuckets("http_request_bytes", cfg.SizeBuckets)
+		reg.SetHistogramBuckets("http_response_bytes", cfg.SizeBuckets)
+	}
+
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
+		// Track in-flight requests; the deferred decrement runs exactly once, even if a
+		// handler panics. Keep the gauge we incremented in case the registry is Reset.
+		inflight := reg.RequestsInflight
+		inflight.Inc()
+		defer inflight.Dec()
+
 		// Process request
 		err := c.Next()
 
+		// Extract tenant if available
+		tenantID, _ := contextx.TenantID(c.UserContext())
+
+		// The error handler runs after this middleware, so derive the status from err
+		status := strconv.Itoa(determineStatus(c, err))
+
+		if cfg.Unmatched != UnmatchedRecord && isUnmatchedRoute(c, err) {
+			if cfg.Unmatched == UnmatchedLabel {
+				reg.RequestsTotal.Inc()
+				reg.IncLabeled("http_requests", map[string]string{
+					"method": c.Method(),
+					"path":   notFoundPathLabel,
+					"status": status,
+					"tenant": tenantID,
+				})
+			}
+			return err
+		}
+
+		if isClientCancelled(c, err) {
+			// Cancelled requests carry no meaningful latency or status
+			reg.RequestsTotal.Inc()
+			reg.IncLabeled("http_requests", map[string]string{
+				"method": c.Method(),
+				"path":   c.Route().Path,
+				"status": strconv.Itoa(StatusClientClosedRequest),
+				"tenant": tenantID,
+			})
+			return err
+		}
+
 		// Record metrics
 		durMs := time.Since(start).Milliseconds()
 		reg.RequestsTotal.Inc()
 		reg.RequestDuration.Observe(durMs)
 
-		// Extract tenant if available
-		tenantID, _ := contextx.TenantID(c.UserContext())
-
 		// Record labeled metric
 		reg.IncLabeled("http_requests", map[string]string{
 			"method": c.Method(),
 			"path":   c.Route().Path,
-			"status": strconv.Itoa(c.Response().StatusCode()),
+			"status": status,
 			"tenant": tenantID,
 		})
 
+		if cfg.RecordSizes {
+			recordSizes(c, reg)
+		}
+
 		return err
 	}
 }
+
+// MetricsHandler returns a handler that serves the registry for scraping. The format
+// is negotiated from the Accept header (see metrics.Registry.Render): OpenMetrics for
+// scrapers that request application/openmetrics-text, otherwise the Prometheus text
+// format. The Content-Type includes the format version.
+//
+// Example usage:
+//
+//	reg := metrics.NewRegistry()
+//	app.Use(middleware.Metrics(reg))
+//	app.Get("/metrics", middleware.MetricsHandler(reg))
+func MetricsHandler(reg *metrics.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		body, contentType := reg.Render(c.Get(fiber.HeaderAccept))
+		c.Set(fiber.HeaderContentType, contentType)
+		c.Set(fiber.HeaderVary, fiber.HeaderAccept)
+		return c.SendString(body)
+	}
+}
+
+// recordSizes observes request and response body sizes.
+func recordSizes(c *fiber.Ctx, reg *metrics.Registry) {
+	labels := map[string]string{
+		"method": c.Method(),
+		"path":   c.Route().Path,
+	}
+
+	reqSize := c.Request().Header.ContentLength()
+	if reqSize < 0 {
+		reqSize = len(c.Body())
+	}
+	reg.ObserveLabeled("http_request_bytes", labels, int64(reqSize))
+
+	resp := c.Response()
+	if resp.IsBodyStream() {
+		// Avoid draining the stream; only record when the length is declared
+		if n := resp.Header.ContentLength(); n >= 0 {
+			reg.ObserveLabeled("http_response_bytes", labels, int64(n))
+		}
+		return
+	}
+	reg.ObserveLabeled("http_response_bytes", labels, int64(len(resp.Body())))
+}
+
+// isUnmatchedRoute reports whether err is Fiber's own error for a request that
+// matched no route handler ("Cannot GET /path" or 405 Method Not Allowed).
+func isUnmatchedRoute(c *fiber.Ctx, err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == fiber.ErrMethodNotAllowed {
+		return true
+	}
+	var fe *fiber.Error
+	return errors.As(err, &fe) && fe.Code == fiber.StatusNotFound &&
+		strings.HasPrefix(fe.Message, "Cannot "+c.Method()+" ")
+}
+
+// isClientCancelled reports whether the request was abandoned by the client: the
+// handler error wraps context.Canceled, or c.UserContext() was cancelled. The fasthttp
+// request context is ignored, since it is cancelled for every request on Shutdown.
+func isClientCancelled(c *fiber.Ctx, err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(c.UserContext().Err(), context.Canceled)
+}
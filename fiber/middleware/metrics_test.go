@@ -0,0 +1,305 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/cubetiqlabs/gopkg/metrics"
+	"github.com/cubetiqlabs/gopkg/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestMetricsRecordsRequests(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Use(Metrics(reg))
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/test", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	if reg.RequestsTotal.Get() != 1 {
+		t.Fatalf("expected 1 request, got %d", reg.RequestsTotal.Get())
+	}
+	if strings.Contains(reg.RenderPrometheus(), "http_response_bytes") {
+		t.Fatal("expected sizes to be skipped by default")
+	}
+}
+
+func TestMetricsRecordSizes(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Use(MetricsWithConfig(reg, MetricsConfig{RecordSizes: true}))
+	app.Post("/echo", func(c *fiber.Ctx) error { return c.SendString("hello") })
+
+	req := httptest.NewRequest("POST", "/echo", strings.NewReader("payload"))
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	out := reg.RenderPrometheus()
+	if !strings.Contains(out, `http_request_bytes_sum{method="POST",path="/echo"} 7`) {
+		t.Fatalf("expected request size to be recorded, got:\n%s", out)
+	}
+	if !strings.Contains(out, `http_response_bytes_sum{method="POST",path="/echo"} 5`) {
+		t.Fatalf("expected response size to be recorded, got:\n%s", out)
+	}
+}
+
+func TestMetricsBuckets(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Use(MetricsWithConfig(reg, MetricsConfig{RecordSizes: true}))
+	app.Post("/echo", func(c *fiber.Ctx) error { return c.SendString("hello") })
+
+	if _, err := app.Test(httptest.NewRequest("POST", "/echo", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	out := reg.RenderPrometheus()
+	for _, want := range []string{
+		`http_request_duration_ms_bucket{le="10000"} 1`,
+		`http_request_duration_ms_bucket{le="+Inf"} 1`,
+		`http_response_bytes_bucket{method="POST",path="/echo",le="256"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q, got:\n%s", want, out)
+		}
+	}
+
+	// Another middleware on the same registry keeps the existing buckets
+	Metrics(reg)
+	MetricsWithConfig(reg, MetricsConfig{LatencyBuckets: []float64{1, 2}, RecordSizes: true, SizeBuckets: []float64{1}})
+	if bounds, _ := reg.RequestDuration.Buckets(); len(bounds) != len(metrics.DefaultLatencyBucketsMs)+1 {
+		t.Fatalf("expected the first middleware's buckets to be kept, got %v", bounds)
+	}
+
+	// A registry with observations but no buckets is left without buckets
+	observed := metrics.NewRegistry()
+	observed.RequestDuration.Observe(1)
+	Metrics(observed)
+	if bounds, _ := observed.RequestDuration.Buckets(); bounds != nil {
+		t.Fatalf("expected no buckets after observations, got %v", bounds)
+	}
+}
+
+func TestMetricsUnmatchedRoutes(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      UnmatchedRoutes
+		wantTotal uint64
+		wantLabel bool
+	}{
+		{"record", UnmatchedRecord, 2, false},
+		{"skip", UnmatchedSkip, 1, false},
+		{"label", UnmatchedLabel, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := metrics.NewRegistry()
+			app := fiber.New()
+			app.Use(MetricsWithConfig(reg, MetricsConfig{Unmatched: tt.mode}))
+			app.Get("/test", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+			for _, path := range []string{"/test", "/wp-admin.php"} {
+				if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+					t.Fatalf("app test: %v", err)
+				}
+			}
+
+			if got := reg.RequestsTotal.Get(); got != tt.wantTotal {
+				t.Fatalf("expected %d requests, got %d", tt.wantTotal, got)
+			}
+			_, ok := reg.LabeledValue("http_requests", map[string]string{
+				"method": "GET", "path": "not_found", "status": "404", "tenant": "",
+			})
+			if ok != tt.wantLabel {
+				t.Fatalf("expected not_found series present=%v", tt.wantLabel)
+			}
+			if tt.mode != UnmatchedRecord && reg.RequestDuration.Count() != 1 {
+				t.Fatalf("expected only the matched request in duration, got %d", reg.RequestDuration.Count())
+			}
+		})
+	}
+}
+
+func TestMetricsLabelsMatchForUnmatchedRoutes(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(contextx.WithTenant(c.UserContext(), "tenant-123"))
+		return c.Next()
+	})
+	app.Use(MetricsWithConfig(reg, MetricsConfig{Unmatched: UnmatchedLabel}))
+	app.Get("/missing", func(c *fiber.Ctx) error { return fiber.ErrNotFound })
+
+	for _, path := range []string{"/missing", "/wp-admin.php"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+	}
+
+	// Both series carry the same label names, and the status of the returned error
+	for _, path := range []string{"/missing", "not_found"} {
+		labels := map[string]string{"method": "GET", "path": path, "status": "404", "tenant": "tenant-123"}
+		if v, ok := reg.LabeledValue("http_requests", labels); !ok || v != 1 {
+			t.Fatalf("expected one request for %v, got %d (present=%v):\n%s", labels, v, ok, reg.RenderPrometheus())
+		}
+	}
+}
+
+func TestMetricsValidationErrorStatus(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Use(Metrics(reg))
+	app.Post("/users", func(c *fiber.Ctx) error {
+		verr := util.NewValidationError()
+		verr.Add("email", "is required")
+		return verr
+	})
+
+	if _, err := app.Test(httptest.NewRequest("POST", "/users", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	if got, _ := reg.LabeledValue("http_requests", map[string]string{
+		"method": "POST", "path": "/users", "status": "422", "tenant": "",
+	}); got != 1 {
+		t.Fatalf("expected validation failure under status 422, got:\n%s", reg.RenderPrometheus())
+	}
+}
+
+func TestMetricsClientCancelled(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Use(Metrics(reg))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithCancel(c.UserContext())
+		c.SetUserContext(ctx)
+		cancel() // client went away mid-request
+		return ctx.Err()
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/slow", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	if got, _ := reg.LabeledValue("http_requests", map[string]string{
+		"method": "GET", "path": "/slow", "status": "499", "tenant": "",
+	}); got != 1 {
+		t.Fatalf("expected cancelled request under status 499, got %d", got)
+	}
+	if reg.RequestsTotal.Get() != 1 {
+		t.Fatalf("expected 1 request, got %d", reg.RequestsTotal.Get())
+	}
+	if reg.RequestDuration.Count() != 0 {
+		t.Fatal("expected cancelled request to be left out of the duration histogram")
+	}
+}
+
+func TestMetricsShutdownNotCountedAsCancelled(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(Metrics(reg))
+	started := make(chan struct{})
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		close(started)
+		<-c.Context().Done() // Closed by Shutdown, not by the client
+		return c.SendString("ok")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go app.Listener(ln)
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+
+	<-started
+	go app.Shutdown()
+	if err := <-done; err != nil {
+		t.Fatalf("request: %v", err)
+	}
+
+	if got, _ := reg.LabeledValue("http_requests", map[string]string{
+		"method": "GET", "path": "/slow", "status": "200", "tenant": "",
+	}); got != 1 {
+		t.Fatalf("expected request completed during shutdown under status 200, got:\n%s", reg.RenderPrometheus())
+	}
+	if reg.RequestDuration.Count() != 1 {
+		t.Fatal("expected request completed during shutdown in the duration histogram")
+	}
+}
+
+func TestMetricsInflightGauge(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Use(Recover())
+	app.Use(Metrics(reg))
+
+	var during float64
+	app.Get("/ok", func(c *fiber.Ctx) error {
+		during = reg.RequestsInflight.Get()
+		return c.SendString("ok")
+	})
+	app.Get("/panic", func(c *fiber.Ctx) error { panic("boom") })
+
+	for _, path := range []string{"/ok", "/panic"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+	}
+
+	if during != 1 {
+		t.Fatalf("expected 1 in-flight request while handling, got %v", during)
+	}
+	if got := reg.RequestsInflight.Get(); got != 0 {
+		t.Fatalf("expected in-flight gauge back at 0 after a panic, got %v", got)
+	}
+	if !strings.Contains(reg.RenderPrometheus(), "http_inflight_requests 0\n") {
+		t.Fatal("expected http_inflight_requests in Prometheus output")
+	}
+}
+
+func TestMetricsHandlerNegotiatesFormat(t *testing.T) {
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Get("/metrics", MetricsHandler(reg))
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", metrics.ContentTypePrometheus},
+		{"application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5", metrics.ContentTypeOpenMetrics},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+		if got := resp.Header.Get("Content-Type"); got != tt.contentType {
+			t.Fatalf("accept %q: expected content type %q, got %q", tt.accept, tt.contentType, got)
+		}
+	}
+}
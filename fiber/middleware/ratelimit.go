@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"math/rand/v2"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cubetiqlabs/gopkg/metrics"
+	"github.com/cubetiqlabs/gopkg/util"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,25 +16,42 @@ const (
 	defaultMaxBuckets       = 10000            // Prevent memory exhaustion
 	bucketCleanupInterval   = 5 * time.Minute  // How often to clean up stale buckets
 	bucketInactiveThreshold = 15 * time.Minute // When to consider a bucket stale
+
+	defaultSaturationRetryMin = 45 * time.Second // Lower bound of Retry-After when at capacity
+	defaultSaturationRetryMax = 75 * time.Second // Upper bound of Retry-After when at capacity
+
+	defaultRateProviderTTL = time.Minute // How long rates resolved by a RatePlanProvider are cached
 )
 
 // RateLimiter implements a token bucket rate limiter per key.
 // It supports:
 // - Per-key rate limiting (tenant, API key, IP, etc.)
+// - A single shared budget for all requests (RateLimitConfig.Global)
 // - Dynamic burst capacity (half of rate)
 // - Automatic bucket cleanup to prevent memory exhaustion
+// - Optional background sweeper for precise expiry of idle buckets
 // - Retry-After header for rejected requests
 type RateLimiter struct {
 	mu          sync.Mutex
 	buckets     map[string]*bucket
-	ratePerMin  int       // Default global rate limit (requests per minute)
-	maxBuckets  int       // Max number of buckets to keep in memory
-	lastCleanup time.Time // Last time we cleaned up stale buckets
+	ratePerMin  int           // Default global rate limit (requests per minute)
+	maxBuckets  int           // Max number of buckets to keep in memory
+	lastCleanup time.Time     // Last time we cleaned up stale buckets
+	idleTTL     time.Duration // How long a bucket may stay inactive before eviction
+
+	// Jittered Retry-After range used when no bucket can be allocated
+	saturationRetryMin time.Duration
+	saturationRetryMax time.Duration
+
+	// Background sweeper state (nil when not running)
+	sweepStop chan struct{}
+	sweepDone chan struct{}
 }
 
 // bucket represents a token bucket for a single key.
 type bucket struct {
 	tokens   float64   // Current token count
+	burst    float64   // Burst capacity at the last take (caps refunds)
 	last     time.Time // Last refill time
 	accessed time.Time // Last access time (for cleanup)
 }
@@ -54,14 +74,283 @@ func NewRateLimiter(ratePerMin int) *RateLimiter {
 		ratePerMin:  ratePerMin,
 		maxBuckets:  defaultMaxBuckets,
 		lastCleanup: time.Now(),
+		idleTTL:     bucketInactiveThreshold,
+
+		saturationRetryMin: defaultSaturationRetryMin,
+		saturationRetryMax: defaultSaturationRetryMax,
+	}
+}
+
+// SetSaturationRetry configures the Retry-After range returned when the limiter is
+// at bucket capacity and cannot evict. A random value within [min, max] is chosen per
+// rejection so clients don't retry in lockstep.
+//
+// Example usage:
+//
+//	limiter.SetSaturationRetry(30*time.Second, 90*time.Second)
+func (rl *RateLimiter) SetSaturationRetry(min, max time.Duration) {
+	if min <= 0 {
+		min = defaultSaturationRetryMin
+	}
+	if max < min {
+		max = min
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.saturationRetryMin = min
+	rl.saturationRetryMax = max
+}
+
+// saturationRetry returns a jittered retry duration within the configured range.
+func (rl *RateLimiter) saturationRetry() time.Duration {
+	spread := rl.saturationRetryMax - rl.saturationRetryMin
+	if spread <= 0 {
+		return rl.saturationRetryMin
+	}
+	return rl.saturationRetryMin + time.Duration(rand.Int64N(int64(spread)+1))
+}
+
+// StartSweeper starts a background goroutine that evicts idle buckets every interval.
+// Buckets inactive for longer than idleTTL are removed, so a key's state expires
+// close to idleTTL instead of waiting for the next lazy cleanup in take.
+// Calling StartSweeper while a sweeper is already running is a no-op.
+//
+// Parameters:
+//   - interval: How often to sweep (default: 5 minutes if <= 0)
+//   - idleTTL: Inactivity threshold for eviction (default: 15 minutes if <= 0)
+//
+// Example usage:
+//
+//	limiter := middleware.NewRateLimiter(600)
+//	limiter.StartSweeper(10*time.Second, time.Minute)
+//	defer limiter.Close()
+func (rl *RateLimiter) StartSweeper(interval, idleTTL time.Duration) {
+	if interval <= 0 {
+		interval = bucketCleanupInterval
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.sweepStop != nil {
+		return
+	}
+	if idleTTL > 0 {
+		rl.idleTTL = idleTTL
+	}
+
+	rl.sweepStop = make(chan struct{})
+	rl.sweepDone = make(chan struct{})
+	go rl.sweep(interval, rl.sweepStop, rl.sweepDone)
+}
+
+// Close stops the background sweeper and waits for it to exit.
+// It is safe to call Close multiple times or when no sweeper is running.
+func (rl *RateLimiter) Close() {
+	rl.mu.Lock()
+	stop, done := rl.sweepStop, rl.sweepDone
+	rl.sweepStop, rl.sweepDone = nil, nil
+	rl.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// sweep periodically evicts idle buckets until stop is closed.
+func (rl *RateLimiter) sweep(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			rl.mu.Lock()
+			rl.cleanupStaleBuckets(now)
+			rl.lastCleanup = now
+			rl.mu.Unlock()
+		}
+	}
+}
+
+// Reset removes the bucket for key so its next request starts with a full burst.
+// Returns false if no bucket existed, i.e. there was nothing to reset.
+//
+// Example usage:
+//
+//	admin.Post("/ratelimit/:key/reset", func(c *fiber.Ctx) error {
+//	    if !limiter.Reset(c.Params("key")) {
+//	        return util.NotFoundError("nothing to reset")
+//	    }
+//	    return c.SendStatus(fiber.StatusNoContent)
+//	})
+func (rl *RateLimiter) Reset(key string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if _, ok := rl.buckets[key]; !ok {
+		return false
 	}
+	delete(rl.buckets, key)
+	return true
+}
+
+// ResetAll removes every bucket and returns how many were cleared.
+func (rl *RateLimiter) ResetAll() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	n := len(rl.buckets)
+	rl.buckets = make(map[string]*bucket)
+	return n
+}
+
+// Refund adds one token back to the bucket for key, up to its burst capacity, so a
+// client isn't penalized for a request that failed on our side before reaching the
+// upstream (e.g. circuit open). Refunds are best-effort: they never raise the bucket
+// above burst, and a bucket evicted in the meantime can't be refunded.
+// Returns false if no bucket exists for key.
+//
+// Use the same key the middleware limits by; with MethodRates that is "<key>|read"
+// or "<key>|write". Refund gives back the default cost of 1; for routes weighted
+// with CostGetter, use RefundN with the request's cost.
+//
+// Example usage:
+//
+//	app.Post("/orders/:id/sync", func(c *fiber.Ctx) error {
+//	    err := breaker.Execute(func() error { return upstream.Sync(c.Params("id")) })
+//	    if errors.Is(err, util.ErrCircuitOpen) {
+//	        limiter.Refund(c.Get("X-Tenant-ID")) // never reached upstream
+//	        return fiber.ErrServiceUnavailable
+//	    }
+//	    return err
+//	})
+func (rl *RateLimiter) Refund(key string) bool {
+	return rl.RefundN(key, 1)
+}
+
+// RefundN adds cost tokens back to the bucket for key, like Refund, for requests
+// charged more than one token via RateLimitConfig.CostGetter. Pass the same cost
+// CostGetter returned for the request so the whole charge is returned; the bucket
+// is still capped at burst. Returns false if no bucket exists for key or cost < 1.
+//
+// Example usage:
+//
+//	cost := exportCost(c) // Also used as the RateLimitConfig.CostGetter
+//	if err := exporter.Run(c.UserContext()); errors.Is(err, util.ErrCircuitOpen) {
+//	    limiter.RefundN(c.Get("X-Tenant-ID"), cost)
+//	    return fiber.ErrServiceUnavailable
+//	}
+func (rl *RateLimiter) RefundN(key string, cost int) bool {
+	if cost < 1 {
+		return false
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		return false
+	}
+	b.tokens += float64(cost)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	return true
+}
+
+// BucketState is the persisted state of a single bucket, as produced by Export.
+type BucketState struct {
+	Tokens float64   `json:"tokens"` // Token count at Last
+	Burst  float64   `json:"burst"`  // Burst capacity at the last take (caps refunds)
+	Last   time.Time `json:"last"`   // Last refill time
+}
+
+// Export returns a snapshot of every bucket, keyed like the limiter's buckets, so
+// the state can be persisted (e.g. to disk or Redis) on shutdown and restored with
+// Import on boot. Tokens are reported as of each bucket's last refill; no refill is
+// applied while exporting.
+//
+// Example usage:
+//
+//	// On shutdown
+//	data, _ := json.Marshal(limiter.Export())
+//	_ = os.WriteFile("ratelimit.json", data, 0o600)
+func (rl *RateLimiter) Export() map[string]BucketState {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	state := make(map[string]BucketState, len(rl.buckets))
+	for key, b := range rl.buckets {
+		state[key] = BucketState{Tokens: b.tokens, Burst: b.burst, Last: b.last}
+	}
+	return state
+}
+
+// Import restores buckets from a snapshot taken with Export, replacing any existing
+// buckets with the same keys, and returns how many were imported. Imported buckets
+// count as accessed now, so they aren't swept right away; keys beyond the limiter's
+// bucket capacity are skipped.
+//
+// Staleness: tokens refill on the next request based on the wall-clock time elapsed
+// since Last, exactly as if the process had kept running, so a snapshot taken
+// minutes ago restores mostly or fully refilled buckets. A Last in the future (clock
+// skew between hosts) is clamped to now.
+//
+// Example usage:
+//
+//	// On boot, before serving traffic
+//	var state map[string]middleware.BucketState
+//	if data, err := os.ReadFile("ratelimit.json"); err == nil && json.Unmarshal(data, &state) == nil {
+//	    limiter.Import(state)
+//	}
+func (rl *RateLimiter) Import(state map[string]BucketState) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	n := 0
+	for key, st := range state {
+		if _, ok := rl.buckets[key]; !ok && len(rl.buckets) >= rl.maxBuckets {
+			continue
+		}
+
+		last := st.Last
+		if last.After(now) {
+			last = now
+		}
+		tokens := st.Tokens
+		if tokens < 0 {
+			tokens = 0
+		}
+		rl.buckets[key] = &bucket{tokens: tokens, burst: st.Burst, last: last, accessed: now}
+		n++
+	}
+	return n
 }
 
 // take attempts to consume one token from the bucket for the given key.
 // Returns:
 // - allowed: true if request is allowed
 // - retryAfter: duration to wait before retrying if rejected
-func (rl *RateLimiter) take(key string, rate int) (allowed bool, retryAfter time.Duration) {
+// - saturated: true if rejected because no bucket could be allocated
+func (rl *RateLimiter) take(key string, rate int) (allowed bool, retryAfter time.Duration, saturated bool) {
+	return rl.takeN(key, rate, 1)
+}
+
+// takeN attempts to consume cost tokens from the bucket for the given key.
+// A cost below 1 is treated as 1; a cost above the burst capacity is clamped to it,
+// so expensive requests still succeed once the bucket is full.
+// Return values match take, with retryAfter accounting for the full cost deficit.
+func (rl *RateLimiter) takeN(key string, rate, cost int) (allowed bool, retryAfter time.Duration, saturated bool) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -80,8 +369,8 @@ func (rl *RateLimiter) take(key string, rate int) (allowed bool, retryAfter time
 		if len(rl.buckets) >= rl.maxBuckets {
 			// Try to evict oldest bucket
 			if !rl.evictOldestBucket(now) {
-				// Could not evict, reject this request
-				return false, time.Minute
+				// Could not evict, reject this request with jitter to avoid a retry stampede
+				return false, rl.saturationRetry(), true
 			}
 		}
 
@@ -95,49 +384,59 @@ func (rl *RateLimiter) take(key string, rate int) (allowed bool, retryAfter time
 			last:     now,
 			accessed: now,
 		}
-		rl.buckets[key] = b
+		rl.buckets[strings.Clone(key)] = b // Key may alias a reused Fiber buffer
 	}
 
 	// Update access time
 	b.accessed = now
 
+	// Burst capacity (half of rate)
+	maxTokens := float64(rate / 2)
+	if maxTokens < 1 {
+		maxTokens = 1
+	}
+	b.burst = maxTokens
+
 	// Refill tokens based on elapsed time
 	elapsed := now.Sub(b.last).Minutes()
 	if elapsed > 0 {
 		b.tokens += elapsed * float64(rate)
-
-		// Cap at burst capacity (half of rate)
-		maxTokens := float64(rate / 2)
-		if maxTokens < 1 {
-			maxTokens = 1
-		}
 		if b.tokens > maxTokens {
 			b.tokens = maxTokens
 		}
 		b.last = now
 	}
 
-	// Try to consume a token
-	if b.tokens >= 1 {
-		b.tokens -= 1
-		return true, 0
+	// Normalize cost to [1, burst capacity]
+	need := float64(cost)
+	if need < 1 {
+		need = 1
+	}
+	if need > maxTokens {
+		need = maxTokens
+	}
+
+	// Try to consume tokens
+	if b.tokens >= need {
+		b.tokens -= need
+		return true, 0, false
 	}
 
 	// Not enough tokens - calculate retry time
-	deficit := 1 - b.tokens
+	deficit := need - b.tokens
 	minutes := deficit / float64(rate)
 	retry := time.Duration(minutes * float64(time.Minute))
 	if retry < time.Second {
 		retry = time.Second
 	}
 
-	return false, retry
+	return false, retry, false
 }
 
 // cleanupStaleBuckets removes buckets that haven't been accessed recently.
 // This prevents memory exhaustion from keeping too many buckets.
 func (rl *RateLimiter) cleanupStaleBuckets(now time.Time) {
-	threshold := now.Add(-bucketInactiveThreshold)
+	threshold := now.Add(-rl.idleTTL)
 	for key, b := range rl.buckets {
 		if b.accessed.Before(threshold) {
 			delete(rl.buckets, key)
@@ -167,8 +466,69 @@ func (rl *RateLimiter) evictOldestBucket(now time.Time) bool {
 	return false
 }
 
+// RatePlanProvider resolves the rate limit (requests per minute) for a rate limit key.
+// Unlike RateGetter it doesn't depend on the HTTP request, so the same provider can
+// back limits keyed by tenant, plan, or API key outside of Fiber handlers.
+// A rate <= 0 means "use the limiter's default rate".
+type RatePlanProvider interface {
+	RateFor(key string) int
+}
+
+// RatePlanProviderFunc adapts an ordinary function to a RatePlanProvider.
+type RatePlanProviderFunc func(key string) int
+
+// RateFor calls f(key).
+func (f RatePlanProviderFunc) RateFor(key string) int {
+	return f(key)
+}
+
+// cachedRateProvider caches rates resolved by a RatePlanProvider for a TTL so lookups
+// backed by a database aren't made on every request.
+type cachedRateProvider struct {
+	provider RatePlanProvider
+	ttl      time.Duration
+	cache    *util.Cache[string, int]
+}
+
+// newCachedRateProvider wraps provider with a TTL cache bounded to maxEntries keys.
+func newCachedRateProvider(provider RatePlanProvider, ttl time.Duration, maxEntries int) *cachedRateProvider {
+	return &cachedRateProvider{
+		provider: provider,
+		ttl:      ttl,
+		cache:    util.NewCache[string, int](maxEntries),
+	}
+}
+
+// RateFor returns the cached rate for key, resolving it from the provider when missing or expired.
+// Concurrent misses for the same key share one provider call (Cache.GetOrLoad loads through a
+// util.Once), so a plan store isn't hit by every request that arrives while a rate expires.
+func (p *cachedRateProvider) RateFor(key string) int {
+	if rate, ok := p.cache.Get(key); ok {
+		return rate
+	}
+
+	// Clone: keys from Fiber headers alias request buffers that are reused
+	key = strings.Clone(key)
+	rate, _ := p.cache.GetOrLoad(key, p.ttl, func() (int, error) {
+		return p.provider.RateFor(key), nil
+	})
+	return rate
+}
+
+// GlobalRateLimitKey is the bucket key shared by every request when
+// RateLimitConfig.Global is set. Pass it to Reset, Refund, or Export lookups.
+const GlobalRateLimitKey = "__global__"
+
 // RateLimitConfig defines configuration for rate limit middleware.
 type RateLimitConfig struct {
+	// Global makes all requests share one bucket (GlobalRateLimitKey), so clients
+	// collectively consume a single budget, e.g. to protect a shared downstream.
+	// KeyGenerator and RateProvider are per-client and setting either panics;
+	// RateGetter, CostGetter, and MethodRates (one read and one write bucket) still apply.
+	// Use a dedicated RateLimiter so the budget isn't shared with per-client limits.
+	// Default: false
+	Global bool
+
 	// KeyGenerator generates a unique key for rate limiting
 	// Default: uses IP address
 	KeyGenerator func(c *fiber.Ctx) string
@@ -176,6 +536,59 @@ type RateLimitConfig struct {
 	// RateGetter returns the rate limit for a specific request
 	// Default: uses the limiter's default rate
 	RateGetter func(c *fiber.Ctx) int
+
+	// RateProvider resolves the rate limit by the key from KeyGenerator.
+	// Mutually exclusive with RateGetter; setting both panics.
+	// Rates <= 0 fall back to the limiter's default rate.
+	// Default: nil
+	RateProvider RatePlanProvider
+
+	// RateProviderTTL is how long rates from RateProvider are cached per key
+	// Default: 1 minute
+	RateProviderTTL time.Duration
+
+	// CostGetter returns how many tokens a request consumes
+	// Refund such requests with RateLimiter.RefundN and the same cost.
+	// Default: 1 for every request
+	CostGetter func(c *fiber.Ctx) int
+
+	// MethodRates sets the rate limit per HTTP method, e.g. {"GET": 1200, "POST": 120}.
+	// Methods not listed use the limiter's default rate. When set, reads (GET, HEAD,
+	// OPTIONS) and writes (all other methods) get separate buckets per key, stored as
+	// "<key>|read" and "<key>|write" (pass those to Reset to unblock a client).
+	// If RateGetter (e.g. per-route rates) or RateProvider is also set, its rate wins
+	// whenever it is > 0; MethodRates applies otherwise.
+	// Default: nil (one bucket per key, no per-method rates)
+	MethodRates map[string]int
+
+	// KeyClassifier maps a request and its rate limit key to a low-cardinality class
+	// (e.g. plan tier, route group) for the rate_limit_total{result,key_class} metric.
+	// Never return the raw key: every distinct class is a separate series.
+	// An empty class is recorded as "other".
+	// Default: every request is classed "default"
+	KeyClassifier func(c *fiber.Ctx, key string) string
+}
+
+// Key classes used when KeyClassifier is unset or returns "".
+const (
+	defaultKeyClass = "default"
+	otherKeyClass   = "other"
+)
+
+// Method classes used to split buckets when MethodRates is configured.
+const (
+	methodClassRead  = "read"
+	methodClassWrite = "write"
+)
+
+// methodClass returns the bucket class for an HTTP method: safe methods are reads.
+func methodClass(method string) string {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return methodClassRead
+	default:
+		return methodClassWrite
+	}
 }
 
 // RateLimitMiddleware returns a Fiber middleware that enforces rate limits.
@@ -184,6 +597,9 @@ type RateLimitConfig struct {
 //   - limiter: The rate limiter instance
 //   - reg: Optional metrics registry for tracking allowed/rejected requests
 //
+// With a registry, each decision increments RateAllowed or RateRejected and the
+// labeled counter rate_limit_total{result="allowed|rejected",key_class="..."}.
+//
 // Example usage:
 //
 //	limiter := middleware.NewRateLimiter(600)
@@ -202,18 +618,138 @@ func RateLimitMiddleware(limiter *RateLimiter, reg *metrics.Registry) fiber.Hand
 //	    KeyGenerator: func(c *fiber.Ctx) string {
 //	        return c.Get("X-API-Key") // Rate limit by API key
 //	    },
+//	    CostGetter: func(c *fiber.Ctx) int {
+//	        if c.Path() == "/export" {
+//	            return 10 // Bulk export consumes 10 tokens
+//	        }
+//	        return 1
+//	    },
+//	}))
+//
+// Resolving rates by key from a plan store (cached for RateProviderTTL):
+//
+//	app.Use(middleware.RateLimitMiddlewareWithConfig(limiter, nil, middleware.RateLimitConfig{
+//	    KeyGenerator: func(c *fiber.Ctx) string {
+//	        return c.Get("X-Tenant-ID")
+//	    },
+//	    RateProvider: middleware.RatePlanProviderFunc(func(tenantID string) int {
+//	        return plans.RequestsPerMinute(tenantID) // Database lookup
+//	    }),
+//	    RateProviderTTL: 5 * time.Minute,
+//	}))
+//
+// Limiting reads and writes differently, with a per-route override:
+//
+//	app.Use(middleware.RateLimitMiddlewareWithConfig(limiter, nil, middleware.RateLimitConfig{
+//	    MethodRates: map[string]int{"GET": 1200, "POST": 120, "PUT": 120, "DELETE": 60},
+//	    RateGetter: func(c *fiber.Ctx) int {
+//	        if c.Path() == "/search" {
+//	            return 300 // Route wins over MethodRates
+//	        }
+//	        return 0 // Fall back to MethodRates
+//	    },
+//	}))
+//
+// Protecting a shared downstream with one budget for all clients:
+//
+//	reports := middleware.NewRateLimiter(120) // 120 req/min across every client
+//	app.Get("/reports/:id", middleware.RateLimitMiddlewareWithConfig(reports, nil, middleware.RateLimitConfig{
+//	    Global: true,
+//	}), getReport)
+//
+// Breaking down throttling by plan tier without labeling by tenant ID:
+//
+//	app.Use(middleware.RateLimitMiddlewareWithConfig(limiter, reg, middleware.RateLimitConfig{
+//	    KeyGenerator: func(c *fiber.Ctx) string {
+//	        return c.Get("X-Tenant-ID")
+//	    },
+//	    KeyClassifier: func(c *fiber.Ctx, tenantID string) string {
+//	        return plans.Tier(tenantID) // "free", "pro", "enterprise"
+//	    },
 //	}))
 func RateLimitMiddlewareWithConfig(limiter *RateLimiter, reg *metrics.Registry, cfg RateLimitConfig) fiber.Handler {
+	if cfg.RateGetter != nil && cfg.RateProvider != nil {
+		panic("ratelimit: RateGetter and RateProvider are mutually exclusive")
+	}
+	if cfg.Global && (cfg.KeyGenerator != nil || cfg.RateProvider != nil) {
+		panic("ratelimit: Global cannot be combined with KeyGenerator or RateProvider")
+	}
+
 	// Set defaults
+	if cfg.Global {
+		cfg.KeyGenerator = func(c *fiber.Ctx) string {
+			return GlobalRateLimitKey
+		}
+	}
 	if cfg.KeyGenerator == nil {
 		cfg.KeyGenerator = func(c *fiber.Ctx) string {
 			return c.IP() // Default: rate limit by IP
 		}
 	}
-	if cfg.RateGetter == nil {
-		cfg.RateGetter = func(c *fiber.Ctx) int {
+
+	// defaultRate is the rate used when no RateGetter or RateProvider rate applies
+	defaultRate := func(c *fiber.Ctx) int {
+		return limiter.ratePerMin
+	}
+	var methodRates map[string]int
+	if len(cfg.MethodRates) > 0 {
+		methodRates = make(map[string]int, len(cfg.MethodRates))
+		for method, rate := range cfg.MethodRates {
+			methodRates[strings.ToUpper(method)] = rate
+		}
+		defaultRate = func(c *fiber.Ctx) int {
+			if rate := methodRates[c.Method()]; rate > 0 {
+				return rate
+			}
 			return limiter.ratePerMin
 		}
+		if cfg.RateGetter != nil {
+			routeRate := cfg.RateGetter
+			cfg.RateGetter = func(c *fiber.Ctx) int {
+				if rate := routeRate(c); rate > 0 {
+					return rate
+				}
+				return defaultRate(c)
+			}
+		}
+	}
+
+	if cfg.RateProvider != nil {
+		if cfg.RateProviderTTL <= 0 {
+			cfg.RateProviderTTL = defaultRateProviderTTL
+		}
+		cfg.RateProvider = newCachedRateProvider(cfg.RateProvider, cfg.RateProviderTTL, limiter.maxBuckets)
+	} else if cfg.RateGetter == nil {
+		cfg.RateGetter = defaultRate
+	}
+	if cfg.CostGetter == nil {
+		cfg.CostGetter = func(c *fiber.Ctx) int {
+			return 1
+		}
+	}
+	if cfg.KeyClassifier == nil {
+		cfg.KeyClassifier = func(c *fiber.Ctx, key string) string {
+			return defaultKeyClass
+		}
+	}
+
+	// recordDecision updates the global and per-class rate limit metrics
+	recordDecision := func(c *fiber.Ctx, key string, allowed bool) {
+		if reg == nil {
+			return
+		}
+		result := "allowed"
+		if allowed {
+			reg.RateAllowed.Inc()
+		} else {
+			reg.RateRejected.Inc()
+			result = "rejected"
+		}
+		class := cfg.KeyClassifier(c, key)
+		if class == "" {
+			class = otherKeyClass
+		}
+		reg.IncLabeled("rate_limit_total", map[string]string{"result": result, "key_class": class})
 	}
 
 	return func(c *fiber.Ctx) error {
@@ -224,29 +760,41 @@ func RateLimitMiddlewareWithConfig(limiter *RateLimiter, reg *metrics.Registry,
 		}
 
 		// Get rate for this request
-		rate := cfg.RateGetter(c)
+		var rate int
+		if cfg.RateProvider != nil {
+			rate = cfg.RateProvider.RateFor(key)
+			if rate <= 0 {
+				rate = defaultRate(c)
+			}
+		} else {
+			rate = cfg.RateGetter(c)
+		}
+
+		// Keep reads and writes in separate buckets when limited per method
+		bucketKey := key
+		if methodRates != nil {
+			bucketKey = key + "|" + methodClass(c.Method())
+		}
 
-		// Check rate limit
-		allowed, retryAfter := limiter.take(key, rate)
+		// Check rate limit, consuming the request's cost
+		allowed, retryAfter, saturated := limiter.takeN(bucketKey, rate, cfg.CostGetter(c))
 
-		if !allowed {
-			// Record rejection metric
-			if reg != nil {
-				reg.RateRejected.Inc()
-			}
+		recordDecision(c, key, allowed)
 
+		if !allowed {
 			// Set Retry-After header
 			c.Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
 
+			// Return 503 when the limiter itself is at capacity, so clients can
+			// distinguish server saturation from their own throttling
+			if saturated {
+				return fiber.NewError(fiber.StatusServiceUnavailable, "rate limiter at capacity")
+			}
+
 			// Return 429 Too Many Requests
 			return fiber.NewError(fiber.StatusTooManyRequests, "rate limit exceeded")
 		}
 
-		// Record allowed metric
-		if reg != nil {
-			reg.RateAllowed.Inc()
-		}
-
 		return c.Next()
 	}
 }
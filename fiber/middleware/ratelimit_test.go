@@ -0,0 +1,499 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRateLimiterTakeAllowsBurstThenRejects(t *testing.T) {
+	limiter := NewRateLimiter(4) // burst = 2
+
+	for i := 0; i < 2; i++ {
+		if allowed, _, _ := limiter.take("k", 4); !allowed {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+
+	allowed, retry, _ := limiter.take("k", 4)
+	if allowed {
+		t.Fatal("expected request to be rejected after burst")
+	}
+	if retry < time.Second {
+		t.Fatalf("expected retry of at least 1s, got %v", retry)
+	}
+}
+
+func TestRateLimiterSweeperEvictsIdleBuckets(t *testing.T) {
+	limiter := NewRateLimiter(600)
+	limiter.StartSweeper(5*time.Millisecond, 10*time.Millisecond)
+	defer limiter.Close()
+
+	limiter.take("idle", 600)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		limiter.mu.Lock()
+		n := len(limiter.buckets)
+		limiter.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("expected idle bucket to be evicted by sweeper")
+}
+
+func TestRateLimiterCloseIdempotent(t *testing.T) {
+	limiter := NewRateLimiter(600)
+	limiter.Close()
+
+	limiter.StartSweeper(time.Millisecond, time.Millisecond)
+	limiter.Close()
+	limiter.Close()
+}
+
+func TestRateLimiterSaturationJitter(t *testing.T) {
+	limiter := NewRateLimiter(600)
+	limiter.maxBuckets = 0 // nothing can be allocated or evicted
+	limiter.SetSaturationRetry(10*time.Second, 20*time.Second)
+
+	for i := 0; i < 20; i++ {
+		allowed, retry, saturated := limiter.take("k", 600)
+		if allowed || !saturated {
+			t.Fatal("expected saturated rejection")
+		}
+		if retry < 10*time.Second || retry > 20*time.Second {
+			t.Fatalf("expected retry within [10s, 20s], got %v", retry)
+		}
+	}
+}
+
+func TestRateLimitMiddlewareSaturationReturns503(t *testing.T) {
+	limiter := NewRateLimiter(600)
+	limiter.maxBuckets = 0
+
+	app := fiber.New()
+	app.Use(RateLimitMiddleware(limiter, nil))
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header")
+	}
+}
+
+func TestRateLimiterTakeNCost(t *testing.T) {
+	limiter := NewRateLimiter(60) // burst = 30, 1 token/sec
+
+	if allowed, _, _ := limiter.takeN("k", 60, 25); !allowed {
+		t.Fatal("expected costly request to be allowed within burst")
+	}
+
+	allowed, retry, _ := limiter.takeN("k", 60, 10)
+	if allowed {
+		t.Fatal("expected request exceeding remaining tokens to be rejected")
+	}
+	// 5 tokens left, 10 needed -> ~5s at 1 token/sec
+	if retry < 4*time.Second || retry > 6*time.Second {
+		t.Fatalf("expected retry around 5s, got %v", retry)
+	}
+}
+
+func TestRateLimiterTakeNClampsCost(t *testing.T) {
+	limiter := NewRateLimiter(4) // burst = 2
+
+	if allowed, _, _ := limiter.takeN("k", 4, 100); !allowed {
+		t.Fatal("expected cost above burst to be clamped and allowed on a full bucket")
+	}
+}
+
+func TestRateLimitMiddlewareRateProvider(t *testing.T) {
+	limiter := NewRateLimiter(600)
+	lookups := map[string]int{}
+	plans := map[string]int{"free": 2, "pro": 600} // free burst = 1
+
+	app := fiber.New()
+	app.Use(RateLimitMiddlewareWithConfig(limiter, nil, RateLimitConfig{
+		KeyGenerator: func(c *fiber.Ctx) string { return c.Get("X-Tenant") },
+		RateProvider: RatePlanProviderFunc(func(key string) int {
+			lookups[strings.Clone(key)]++
+			return plans[key]
+		}),
+	}))
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	do := func(tenant string) int {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Tenant", tenant)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if code := do("free"); code != fiber.StatusOK {
+		t.Fatalf("expected first free request allowed, got %d", code)
+	}
+	if code := do("free"); code != fiber.StatusTooManyRequests {
+		t.Fatalf("expected second free request limited, got %d", code)
+	}
+	for i := 0; i < 3; i++ {
+		if code := do("pro"); code != fiber.StatusOK {
+			t.Fatalf("expected pro request %d allowed, got %d", i, code)
+		}
+	}
+	if lookups["free"] != 1 || lookups["pro"] != 1 {
+		t.Fatalf("expected one cached lookup per key, got %v", lookups)
+	}
+}
+
+func TestCachedRateProviderExpires(t *testing.T) {
+	calls := 0
+	p := newCachedRateProvider(RatePlanProviderFunc(func(string) int {
+		calls++
+		return calls
+	}), time.Millisecond, 10)
+
+	if rate := p.RateFor("k"); rate != 1 {
+		t.Fatalf("expected 1, got %d", rate)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if rate := p.RateFor("k"); rate != 2 {
+		t.Fatalf("expected refreshed rate 2, got %d", rate)
+	}
+}
+
+func TestCachedRateProviderSharesConcurrentMisses(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	p := newCachedRateProvider(RatePlanProviderFunc(func(string) int {
+		calls.Add(1)
+		<-release
+		return 100
+	}), time.Minute, 10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rate := p.RateFor("tenant-1"); rate != 100 {
+				t.Errorf("expected shared rate 100, got %d", rate)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // Let every lookup miss and join the load
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected one provider call for concurrent misses, got %d", n)
+	}
+}
+
+func TestRateLimitMiddlewareRateGetterAndProviderPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic when both RateGetter and RateProvider are set")
+		}
+	}()
+	RateLimitMiddlewareWithConfig(NewRateLimiter(600), nil, RateLimitConfig{
+		RateGetter:   func(*fiber.Ctx) int { return 1 },
+		RateProvider: RatePlanProviderFunc(func(string) int { return 1 }),
+	})
+}
+
+func TestRateLimiterReset(t *testing.T) {
+	limiter := NewRateLimiter(2) // burst = 1
+
+	if allowed, _, _ := limiter.take("k", 2); !allowed {
+		t.Fatal("expected first request to be allowed")
+	}
+	if allowed, _, _ := limiter.take("k", 2); allowed {
+		t.Fatal("expected second request to be rejected")
+	}
+
+	if !limiter.Reset("k") {
+		t.Fatal("expected Reset to report an existing bucket")
+	}
+	if limiter.Reset("missing") {
+		t.Fatal("expected Reset to report nothing to reset")
+	}
+	if allowed, _, _ := limiter.take("k", 2); !allowed {
+		t.Fatal("expected request to be allowed after reset")
+	}
+
+	limiter.take("other", 2)
+	if n := limiter.ResetAll(); n != 2 {
+		t.Fatalf("expected 2 buckets cleared, got %d", n)
+	}
+}
+
+func TestRateLimitMiddlewareKeyClassMetrics(t *testing.T) {
+	limiter := NewRateLimiter(2) // burst = 1
+	reg := metrics.NewRegistry()
+	app := fiber.New()
+	app.Use(RateLimitMiddlewareWithConfig(limiter, reg, RateLimitConfig{
+		KeyGenerator: func(c *fiber.Ctx) string { return c.Get("X-Tenant") },
+		KeyClassifier: func(c *fiber.Ctx, key string) string {
+			if strings.HasPrefix(key, "free-") {
+				return "free"
+			}
+			return ""
+		},
+	}))
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	for _, tenant := range []string{"free-a", "free-a", "free-b", "acme"} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Tenant", tenant)
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+	}
+
+	counts := map[[2]string]uint64{
+		{"allowed", "free"}:  2,
+		{"rejected", "free"}: 1,
+		{"allowed", "other"}: 1,
+	}
+	for labels, want := range counts {
+		got, _ := reg.LabeledValue("rate_limit_total", map[string]string{"result": labels[0], "key_class": labels[1]})
+		if got != want {
+			t.Fatalf("rate_limit_total%v = %d, want %d", labels, got, want)
+		}
+	}
+	if reg.RateAllowed.Get() != 3 || reg.RateRejected.Get() != 1 {
+		t.Fatalf("expected global counters 3/1, got %d/%d", reg.RateAllowed.Get(), reg.RateRejected.Get())
+	}
+}
+
+func TestRateLimitMiddlewareMethodRates(t *testing.T) {
+	limiter := NewRateLimiter(600)
+	app := fiber.New()
+	app.Use(RateLimitMiddlewareWithConfig(limiter, nil, RateLimitConfig{
+		KeyGenerator: func(*fiber.Ctx) string { return "client" },
+		MethodRates:  map[string]int{"get": 600, "POST": 2}, // POST burst = 1
+	}))
+	app.Get("/items", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Post("/items", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	do := func(method string) int {
+		resp, err := app.Test(httptest.NewRequest(method, "/items", nil))
+		if err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if code := do("POST"); code != fiber.StatusOK {
+		t.Fatalf("expected first write to be allowed, got %d", code)
+	}
+	if code := do("POST"); code != fiber.StatusTooManyRequests {
+		t.Fatalf("expected second write to be limited, got %d", code)
+	}
+	// Reads use their own bucket and rate
+	for i := 0; i < 5; i++ {
+		if code := do("GET"); code != fiber.StatusOK {
+			t.Fatalf("expected read %d to be allowed, got %d", i, code)
+		}
+	}
+
+	if !limiter.Reset("client|write") {
+		t.Fatal("expected a separate write bucket")
+	}
+}
+
+func TestRateLimitMiddlewareRouteRateWinsOverMethodRates(t *testing.T) {
+	var got []int
+	limiter := NewRateLimiter(600)
+	app := fiber.New()
+	app.Use(RateLimitMiddlewareWithConfig(limiter, nil, RateLimitConfig{
+		MethodRates: map[string]int{"GET": 1200},
+		RateGetter: func(c *fiber.Ctx) int {
+			if c.Path() == "/search" {
+				return 300
+			}
+			return 0
+		},
+	}))
+	app.Use(func(c *fiber.Ctx) error {
+		limiter.mu.Lock()
+		for _, b := range limiter.buckets {
+			got = append(got, int(b.tokens))
+		}
+		limiter.mu.Unlock()
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/search", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	limiter.ResetAll()
+	if _, err := app.Test(httptest.NewRequest("GET", "/items", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	// Initial burst is rate/2, minus the request's cost
+	if len(got) != 2 || got[0] != 149 || got[1] != 599 {
+		t.Fatalf("expected route rate 300 then method rate 1200, got buckets %v", got)
+	}
+}
+
+func TestRateLimiterRefund(t *testing.T) {
+	limiter := NewRateLimiter(4) // burst = 2
+
+	if limiter.Refund("k") {
+		t.Fatal("expected Refund to report a missing bucket")
+	}
+
+	limiter.take("k", 4)
+	limiter.take("k", 4)
+	if allowed, _, _ := limiter.take("k", 4); allowed {
+		t.Fatal("expected request to be rejected after burst")
+	}
+
+	if !limiter.Refund("k") {
+		t.Fatal("expected Refund to report an existing bucket")
+	}
+	if allowed, _, _ := limiter.take("k", 4); !allowed {
+		t.Fatal("expected refunded token to allow a request")
+	}
+}
+
+func TestRateLimiterRefundN(t *testing.T) {
+	limiter := NewRateLimiter(40) // burst = 20
+
+	if allowed, _, _ := limiter.takeN("k", 40, 10); !allowed {
+		t.Fatal("expected weighted request to be allowed")
+	}
+	if limiter.RefundN("k", 0) {
+		t.Fatal("expected RefundN to reject a non-positive cost")
+	}
+	if !limiter.RefundN("k", 10) {
+		t.Fatal("expected RefundN to report an existing bucket")
+	}
+
+	limiter.mu.Lock()
+	tokens := limiter.buckets["k"].tokens
+	limiter.mu.Unlock()
+	if tokens < 19.99 {
+		t.Fatalf("expected the whole cost to be refunded, got %v tokens", tokens)
+	}
+}
+
+func TestRateLimiterRefundCappedAtBurst(t *testing.T) {
+	limiter := NewRateLimiter(4) // burst = 2
+
+	limiter.take("k", 4)
+	for i := 0; i < 5; i++ {
+		limiter.Refund("k")
+	}
+
+	limiter.mu.Lock()
+	tokens := limiter.buckets["k"].tokens
+	limiter.mu.Unlock()
+	if tokens != 2 {
+		t.Fatalf("expected refunds to be capped at burst 2, got %v", tokens)
+	}
+}
+
+func TestRateLimiterExportImport(t *testing.T) {
+	limiter := NewRateLimiter(4) // burst = 2
+	limiter.take("k", 4)
+	limiter.take("k", 4)
+
+	state := limiter.Export()
+	if st := state["k"]; st.Tokens > 0.01 || st.Burst != 2 {
+		t.Fatalf("unexpected exported state: %+v", st)
+	}
+
+	// A fresh limiter restored from the snapshot keeps the bucket drained
+	restored := NewRateLimiter(4)
+	if n := restored.Import(state); n != 1 {
+		t.Fatalf("expected 1 bucket imported, got %d", n)
+	}
+	if allowed, _, _ := restored.take("k", 4); allowed {
+		t.Fatal("expected drained bucket to reject after import")
+	}
+}
+
+func TestRateLimiterImportRefillsByElapsedTime(t *testing.T) {
+	limiter := NewRateLimiter(60)
+	limiter.Import(map[string]BucketState{
+		"stale":  {Tokens: 0, Burst: 30, Last: time.Now().Add(-time.Minute)},
+		"future": {Tokens: 0, Burst: 30, Last: time.Now().Add(time.Hour)},
+	})
+
+	// A minute of elapsed wall-clock time refills the stale bucket
+	if allowed, _, _ := limiter.take("stale", 60); !allowed {
+		t.Fatal("expected stale bucket to have refilled")
+	}
+
+	// A future Last is clamped to now, so the bucket stays empty rather than blocked for an hour
+	_, retry, _ := limiter.take("future", 60)
+	if retry > time.Minute {
+		t.Fatalf("expected future Last to be clamped, got retry %v", retry)
+	}
+}
+
+func TestRateLimitMiddlewareGlobal(t *testing.T) {
+	limiter := NewRateLimiter(4) // burst = 2
+	app := fiber.New()
+	app.Use(RateLimitMiddlewareWithConfig(limiter, nil, RateLimitConfig{Global: true}))
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	do := func(ip string) int {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = ip + ":1234"
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app test: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	// Different clients draw from the same bucket
+	if code := do("10.0.0.1"); code != fiber.StatusOK {
+		t.Fatalf("expected first request allowed, got %d", code)
+	}
+	if code := do("10.0.0.2"); code != fiber.StatusOK {
+		t.Fatalf("expected second request allowed, got %d", code)
+	}
+	if code := do("10.0.0.3"); code != fiber.StatusTooManyRequests {
+		t.Fatalf("expected third client limited by the shared budget, got %d", code)
+	}
+
+	if !limiter.Reset(GlobalRateLimitKey) {
+		t.Fatal("expected the global bucket to exist")
+	}
+	if code := do("10.0.0.3"); code != fiber.StatusOK {
+		t.Fatalf("expected request allowed after reset, got %d", code)
+	}
+}
+
+func TestRateLimitMiddlewareGlobalWithKeyGeneratorPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic when Global is combined with KeyGenerator")
+		}
+	}()
+	RateLimitMiddlewareWithConfig(NewRateLimiter(600), nil, RateLimitConfig{
+		Global:       true,
+		KeyGenerator: func(c *fiber.Ctx) string { return c.IP() },
+	})
+}
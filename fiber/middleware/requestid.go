@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 
+	"github.com/cubetiqlabs/gopkg/contextx"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -31,6 +32,22 @@ func RequestID() fiber.Handler {
 	}
 }
 
+// GetRequestID returns the current request ID for propagating on outbound calls.
+// It reads the "request_id" local set by RequestID, falling back to
+// contextx.RequestID(c.UserContext()). Returns "" if neither is set.
+// The result is a copy, safe to use after the handler returns.
+//
+// Example usage:
+//
+//	req.Header.Set(middleware.RequestIDHeader, middleware.GetRequestID(c))
+func GetRequestID(c *fiber.Ctx) string {
+	if rid := localString(c, "request_id"); rid != "" {
+		return rid
+	}
+	rid, _ := contextx.RequestID(c.UserContext())
+	return rid
+}
+
 // newRID generates a cryptographically random request ID.
 // It uses 16 random bytes encoded as base64url without padding (22 characters).
 // This provides ~128 bits of entropy, making collisions extremely unlikely.
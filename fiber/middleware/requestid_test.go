@@ -4,6 +4,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/cubetiqlabs/gopkg/contextx"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -83,3 +84,34 @@ func TestNewRIDGeneratesUnique(t *testing.T) {
 		}
 	}
 }
+
+func TestGetRequestID(t *testing.T) {
+	var fromLocals, fromContext string
+
+	app := fiber.New()
+	app.Get("/locals", RequestID(), func(c *fiber.Ctx) error {
+		fromLocals = GetRequestID(c)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Get("/context", func(c *fiber.Ctx) error {
+		c.SetUserContext(contextx.WithRequestID(c.UserContext(), "ctx-rid"))
+		fromContext = GetRequestID(c)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("GET", "/locals", nil)
+	req.Header.Set(RequestIDHeader, "hdr-rid")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if _, err := app.Test(httptest.NewRequest("GET", "/context", nil)); err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+
+	if fromLocals != "hdr-rid" {
+		t.Fatalf("expected request ID from locals, got %q", fromLocals)
+	}
+	if fromContext != "ctx-rid" {
+		t.Fatalf("expected request ID from context, got %q", fromContext)
+	}
+}
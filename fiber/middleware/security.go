@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -23,8 +25,27 @@ type SecurityHeadersConfig struct {
 
 	// EnableContentTypeNosniff enables X-Content-Type-Options header (default: true)
 	EnableContentTypeNosniff bool
+
+	// CrossOriginOpenerPolicy sets Cross-Origin-Opener-Policy (default: "" = not set)
+	// Allowed: unsafe-none, same-origin-allow-popups, same-origin, noopener-allow-popups
+	CrossOriginOpenerPolicy string
+
+	// CrossOriginEmbedderPolicy sets Cross-Origin-Embedder-Policy (default: "" = not set)
+	// Allowed: unsafe-none, require-corp, credentialless
+	CrossOriginEmbedderPolicy string
+
+	// CrossOriginResourcePolicy sets Cross-Origin-Resource-Policy (default: "" = not set)
+	// Allowed: same-site, same-origin, cross-origin
+	CrossOriginResourcePolicy string
 }
 
+// Allowed keywords for the cross-origin isolation headers.
+var (
+	allowedCOOP = []string{"unsafe-none", "same-origin-allow-popups", "same-origin", "noopener-allow-popups"}
+	allowedCOEP = []string{"unsafe-none", "require-corp", "credentialless"}
+	allowedCORP = []string{"same-site", "same-origin", "cross-origin"}
+)
+
 // SecurityHeaders returns a middleware that sets secure HTTP headers with default configuration.
 // This helps protect against common web vulnerabilities including:
 // - Clickjacking (X-Frame-Options)
@@ -40,6 +61,7 @@ func SecurityHeaders() fiber.Handler {
 }
 
 // SecurityHeadersWithConfig returns a security headers middleware with custom configuration.
+// It panics if a cross-origin policy value is not an allowed keyword.
 //
 // Example usage:
 //
@@ -47,7 +69,20 @@ func SecurityHeaders() fiber.Handler {
 //	    HSTSMaxAge: 63072000, // 2 years
 //	    ContentSecurityPolicy: "default-src 'self'",
 //	}))
+//
+// Cross-origin isolation (e.g. for SharedArrayBuffer):
+//
+//	app.Use(middleware.SecurityHeadersWithConfig(middleware.SecurityHeadersConfig{
+//	    CrossOriginOpenerPolicy:   "same-origin",
+//	    CrossOriginEmbedderPolicy: "require-corp",
+//	    CrossOriginResourcePolicy: "same-origin",
+//	}))
 func SecurityHeadersWithConfig(cfg SecurityHeadersConfig) fiber.Handler {
+	// Validate cross-origin policies up front so misconfiguration fails at startup
+	validatePolicy("Cross-Origin-Opener-Policy", cfg.CrossOriginOpenerPolicy, allowedCOOP)
+	validatePolicy("Cross-Origin-Embedder-Policy", cfg.CrossOriginEmbedderPolicy, allowedCOEP)
+	validatePolicy("Cross-Origin-Resource-Policy", cfg.CrossOriginResourcePolicy, allowedCORP)
+
 	// Set defaults
 	if cfg.HSTSMaxAge == 0 {
 		cfg.HSTSMaxAge = 31536000 // 1 year in seconds
@@ -98,6 +133,30 @@ func SecurityHeadersWithConfig(cfg SecurityHeadersConfig) fiber.Handler {
 		// Permissions-Policy: Control browser features
 		c.Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
 
+		// Cross-origin isolation headers (opt-in)
+		if cfg.CrossOriginOpenerPolicy != "" {
+			c.Set("Cross-Origin-Opener-Policy", cfg.CrossOriginOpenerPolicy)
+		}
+		if cfg.CrossOriginEmbedderPolicy != "" {
+			c.Set("Cross-Origin-Embedder-Policy", cfg.CrossOriginEmbedderPolicy)
+		}
+		if cfg.CrossOriginResourcePolicy != "" {
+			c.Set("Cross-Origin-Resource-Policy", cfg.CrossOriginResourcePolicy)
+		}
+
 		return c.Next()
 	}
 }
+
+// validatePolicy panics if value is set and not one of the allowed keywords.
+func validatePolicy(header, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	panic(fmt.Sprintf("invalid %s value %q (allowed: %s)", header, value, strings.Join(allowed, ", ")))
+}
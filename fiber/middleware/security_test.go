@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSecurityHeadersCrossOriginUnsetByDefault(t *testing.T) {
+	app := fiber.New()
+	app.Use(SecurityHeaders())
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if resp.Header.Get("X-Frame-Options") != "DENY" {
+		t.Fatal("expected X-Frame-Options to be set")
+	}
+	if resp.Header.Get("Cross-Origin-Opener-Policy") != "" {
+		t.Fatal("expected COOP to be unset by default")
+	}
+}
+
+func TestSecurityHeadersCrossOriginIsolation(t *testing.T) {
+	app := fiber.New()
+	app.Use(SecurityHeadersWithConfig(SecurityHeadersConfig{
+		CrossOriginOpenerPolicy:   "same-origin",
+		CrossOriginEmbedderPolicy: "require-corp",
+		CrossOriginResourcePolicy: "same-site",
+	}))
+	app.Get("/test", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	if got := resp.Header.Get("Cross-Origin-Opener-Policy"); got != "same-origin" {
+		t.Fatalf("expected COOP same-origin, got %q", got)
+	}
+	if got := resp.Header.Get("Cross-Origin-Embedder-Policy"); got != "require-corp" {
+		t.Fatalf("expected COEP require-corp, got %q", got)
+	}
+	if got := resp.Header.Get("Cross-Origin-Resource-Policy"); got != "same-site" {
+		t.Fatalf("expected CORP same-site, got %q", got)
+	}
+}
+
+func TestSecurityHeadersInvalidPolicyPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected invalid COEP value to panic")
+		}
+	}()
+	SecurityHeadersWithConfig(SecurityHeadersConfig{CrossOriginEmbedderPolicy: "same-origin"})
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantResolver returns a middleware that resolves the tenant for each request and
+// stores it in c.UserContext() with contextx.WithTenant, so Metrics, AccessLog with
+// IncludeContextFields, logging, and outbound calls see it. Requests for which resolve
+// returns false (or "") pass through without a tenant; reject them in a later
+// middleware if a tenant is required.
+//
+// Register it before Metrics and AccessLog, and after any auth middleware resolve
+// depends on. Use TenantFromHeader or TenantFromSubdomain for the common cases.
+//
+// Panics if resolve is nil.
+//
+// Example usage:
+//
+//	app.Use(middleware.RequestID())
+//	app.Use(middleware.TenantResolver(middleware.TenantFromSubdomain("app.example.com")))
+//	app.Use(middleware.Metrics(reg))
+//
+// Resolving from an authenticated claim:
+//
+//	app.Use(middleware.TenantResolver(func(c *fiber.Ctx) (string, bool) {
+//	    claims, ok := c.Locals("claims").(*Claims)
+//	    if !ok {
+//	        return "", false
+//	    }
+//	    return claims.TenantID, true
+//	}))
+func TenantResolver(resolve func(c *fiber.Ctx) (string, bool)) fiber.Handler {
+	if resolve == nil {
+		panic("tenant resolver: resolve function is required")
+	}
+
+	return func(c *fiber.Ctx) error {
+		if tenantID, ok := resolve(c); ok && tenantID != "" {
+			// Clone: values read from the request alias buffers Fiber reuses
+			c.SetUserContext(contextx.WithTenant(c.UserContext(), strings.Clone(tenantID)))
+		}
+		return c.Next()
+	}
+}
+
+// TenantFromHeader returns a TenantResolver function reading the tenant from the
+// named request header, e.g. "X-Tenant-ID". Only trust such a header when it is set
+// by a gateway that authenticates the caller.
+func TenantFromHeader(header string) func(c *fiber.Ctx) (string, bool) {
+	return func(c *fiber.Ctx) (string, bool) {
+		tenantID := strings.TrimSpace(c.Get(header))
+		return tenantID, tenantID != ""
+	}
+}
+
+// TenantFromSubdomain returns a TenantResolver function reading the tenant from the
+// subdomain directly below baseDomain, lower-cased: with baseDomain "app.example.com",
+// "acme.app.example.com" resolves to "acme". The bare base domain, other hosts, and
+// deeper subdomains such as "x.acme.app.example.com" resolve to no tenant.
+func TenantFromSubdomain(baseDomain string) func(c *fiber.Ctx) (string, bool) {
+	suffix := "." + strings.ToLower(strings.Trim(baseDomain, "."))
+	return func(c *fiber.Ctx) (string, bool) {
+		host := c.Hostname()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+
+		label, ok := strings.CutSuffix(host, suffix)
+		if !ok || label == "" || strings.Contains(label, ".") {
+			return "", false
+		}
+		return label, true
+	}
+}
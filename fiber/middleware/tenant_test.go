@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/gofiber/fiber/v2"
+)
+
+// resolvedTenant sends req and returns the response body, which the test handlers
+// set to the tenant found in c.UserContext().
+func resolvedTenant(t *testing.T, app *fiber.App, req *http.Request) string {
+	t.Helper()
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app test: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	return string(data)
+}
+
+func TestTenantResolverSetsUserContext(t *testing.T) {
+	app := fiber.New()
+	app.Use(TenantResolver(TenantFromHeader("X-Tenant-ID")))
+	app.Get("/", func(c *fiber.Ctx) error {
+		tenantID, _ := contextx.TenantID(c.UserContext())
+		return c.SendString(tenantID)
+	})
+
+	for header, want := range map[string]string{"acme": "acme", "": ""} {
+		req := httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			req.Header.Set("X-Tenant-ID", header)
+		}
+		if got := resolvedTenant(t, app, req); got != want {
+			t.Fatalf("header %q: expected tenant %q, got %q", header, want, got)
+		}
+	}
+}
+
+func TestTenantFromSubdomain(t *testing.T) {
+	app := fiber.New()
+	app.Use(TenantResolver(TenantFromSubdomain("app.example.com")))
+	app.Get("/", func(c *fiber.Ctx) error {
+		tenantID, _ := contextx.TenantID(c.UserContext())
+		return c.SendString(tenantID)
+	})
+
+	cases := map[string]string{
+		"acme.app.example.com":      "acme",
+		"ACME.app.example.com:8443": "acme",
+		"app.example.com":           "",
+		"x.acme.app.example.com":    "",
+		"acme.other.com":            "",
+	}
+	for host, want := range cases {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Host = host
+		if got := resolvedTenant(t, app, req); got != want {
+			t.Fatalf("host %q: expected tenant %q, got %q", host, want, got)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/cubetiqlabs/gopkg/logging"
+	"github.com/cubetiqlabs/gopkg/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDMetadataKey is the metadata key used for request IDs.
+// gRPC metadata keys are lowercase, mirroring the X-Request-ID HTTP header.
+const RequestIDMetadataKey = "x-request-id"
+
+// UnaryServerConfig defines configuration for the unary server interceptor.
+type UnaryServerConfig struct {
+	// Logger is the zap logger used for request start/end logs (optional)
+	// When nil, the global logger from logging.Init is used; if it isn't initialized
+	// either, request logging is skipped
+	Logger *zap.Logger
+
+	// Registry records gRPC request count and duration (optional)
+	Registry *metrics.Registry
+}
+
+// UnaryServerInterceptor returns a unary interceptor with default configuration.
+// Requests are logged with the global logger once logging.Init has been called;
+// before that it only handles request ID propagation.
+//
+// Example usage:
+//
+//	srv := grpc.NewServer(grpc.UnaryInterceptor(interceptor.UnaryServerInterceptor()))
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return UnaryServerInterceptorWithConfig(UnaryServerConfig{})
+}
+
+// UnaryServerInterceptorWithConfig returns a unary interceptor that mirrors the
+// Fiber RequestID and AccessLog middleware for gRPC services.
+//
+// For each call it:
+// - Preserves an incoming x-request-id metadata value or generates a new one
+// - Stores the ID in context via contextx.WithRequestID
+// - Returns the ID to the client in the response header metadata
+// - Stores a request-scoped logger for logging.FromContext
+// - Logs request start and end with method, status code, and duration
+//
+// Example usage:
+//
+//	logger, _ := logging.Init("info", false)
+//	srv := grpc.NewServer(grpc.UnaryInterceptor(
+//	    interceptor.UnaryServerInterceptorWithConfig(interceptor.UnaryServerConfig{
+//	        Logger:   logger,
+//	        Registry: reg,
+//	    }),
+//	))
+func UnaryServerInterceptorWithConfig(cfg UnaryServerConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		rid := requestIDFromMetadata(ctx)
+		if rid == "" {
+			rid = newRID()
+		}
+		ctx = contextx.WithRequestID(ctx, rid)
+
+		// Echo the request ID back to the client (ignored when no transport stream exists)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, rid))
+
+		// Resolved per call, so a global logger initialized after startup is picked up
+		logger := cfg.Logger
+		if logger == nil {
+			logger, _ = logging.Global()
+		}
+		if logger != nil {
+			logger = logger.With(zap.String("request_id", rid))
+			ctx = logging.WithLogger(ctx, logger)
+			logger.Info("grpc request started", zap.String("method", info.FullMethod))
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		duration := time.Since(start)
+
+		if cfg.Registry != nil {
+			cfg.Registry.GrpcRequests.Inc()
+			cfg.Registry.GrpcDuration.Observe(duration.Milliseconds())
+		}
+
+		if logger != nil {
+			code := status.Code(err)
+			fields := []zap.Field{
+				zap.String("method", info.FullMethod),
+				zap.String("code", code.String()),
+				zap.Duration("duration", duration),
+			}
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+				logger.Error("grpc request finished", fields...)
+			} else {
+				logger.Info("grpc request finished", fields...)
+			}
+		}
+
+		return resp, err
+	}
+}
+
+// requestIDFromMetadata returns the first x-request-id value from incoming metadata.
+func requestIDFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if vals := md.Get(RequestIDMetadataKey); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// newRID generates a cryptographically random request ID.
+// It matches the format used by the Fiber RequestID middleware (22 base64url characters).
+func newRID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return base64.RawURLEncoding.EncodeToString([]byte("fallback"))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
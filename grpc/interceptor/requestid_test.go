@@ -0,0 +1,105 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/cubetiqlabs/gopkg/logging"
+	"github.com/cubetiqlabs/gopkg/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+func TestUnaryServerInterceptorGeneratesRequestID(t *testing.T) {
+	var rid string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		rid, _ = contextx.RequestID(ctx)
+		return "ok", nil
+	}
+
+	_, err := UnaryServerInterceptor()(context.Background(), nil, testInfo, handler)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rid) != 22 {
+		t.Fatalf("expected generated ID of length 22, got %q", rid)
+	}
+}
+
+func TestUnaryServerInterceptorPreservesIncoming(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadataKey, "upstream-id"))
+
+	var rid string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		rid, _ = contextx.RequestID(ctx)
+		return nil, nil
+	}
+
+	_, _ = UnaryServerInterceptor()(ctx, nil, testInfo, handler)
+	if rid != "upstream-id" {
+		t.Fatalf("expected upstream-id, got %q", rid)
+	}
+}
+
+func TestUnaryServerInterceptorRecordsMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	intercept := UnaryServerInterceptorWithConfig(UnaryServerConfig{
+		Logger:   zap.NewNop(),
+		Registry: reg,
+	})
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}
+
+	_, err := intercept(context.Background(), nil, testInfo, handler)
+	if err == nil {
+		t.Fatal("expected handler error to be returned")
+	}
+	if reg.GrpcRequests.Get() != 1 {
+		t.Fatalf("expected 1 grpc request, got %d", reg.GrpcRequests.Get())
+	}
+	if reg.GrpcDuration.Count() != 1 {
+		t.Fatalf("expected 1 duration observation, got %d", reg.GrpcDuration.Count())
+	}
+}
+
+func TestUnaryServerInterceptorFallsBackToGlobalLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if _, err := logging.InitWithOptions(logging.Options{OutputPaths: []string{path}}); err != nil {
+		t.Fatalf("init logging: %v", err)
+	}
+
+	var scoped bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		logging.FromContext(ctx).Info("handling")
+		scoped = true
+		return "ok", nil
+	}
+	if _, err := UnaryServerInterceptor()(context.Background(), nil, testInfo, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = logging.Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{"grpc request started", "grpc request finished", `"request_id"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in global logger output, got:\n%s", want, out)
+		}
+	}
+	if !scoped || strings.Count(out, `"request_id"`) != 3 {
+		t.Fatalf("expected the handler to log through the request-scoped logger, got:\n%s", out)
+	}
+}
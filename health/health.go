@@ -0,0 +1,8 @@
+// Package health provides ready-made dependency probes for health and readiness checks.
+package health
+
+import "context"
+
+// Check probes one dependency and returns nil if it is healthy. It must return
+// promptly once ctx is done, so a health handler can bound the time spent on each probe.
+type Check func(ctx context.Context) error
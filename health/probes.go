@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// PingDB returns a Check that pings db, opening a connection if none is idle.
+// Panics if db is nil.
+//
+// Example usage:
+//
+//	checks := map[string]health.Check{
+//	    "postgres": health.PingDB(db),
+//	}
+func PingDB(db *sql.DB) Check {
+	if db == nil {
+		panic("health: PingDB requires a database")
+	}
+	return func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("database ping failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// PingHTTP returns a Check that sends a GET request to url and expects a 2xx or 3xx
+// response. Redirects are not followed. The request is bounded by timeout as well as
+// by the context passed to the Check, whichever ends first (timeout <= 0 = context only).
+//
+// Example usage:
+//
+//	checks := map[string]health.Check{
+//	    "payments": health.PingHTTP("http://payments.internal/healthz", 2*time.Second),
+//	}
+func PingHTTP(url string, timeout time.Duration) Check {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("http check %s: %w", url, err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("http check %s: %w", url, err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096)) // Allow connection reuse
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("http check %s: unexpected status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// PingTCP returns a Check that opens a TCP connection to addr ("host:port") and
+// closes it again. The dial is bounded by timeout as well as by the context passed
+// to the Check, whichever ends first (timeout <= 0 = context only).
+//
+// Example usage:
+//
+//	checks := map[string]health.Check{
+//	    "redis": health.PingTCP("redis:6379", time.Second),
+//	}
+func PingTCP(addr string, timeout time.Duration) Check {
+	var dialer net.Dialer
+	return func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("tcp check %s: %w", addr, err)
+		}
+		return conn.Close()
+	}
+}
+
+// withTimeout derives a context ending after timeout, or returns ctx unchanged
+// (with a no-op cancel) if timeout <= 0.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pingDriver is a database/sql driver whose connections fail Ping with pingErr.
+type pingDriver struct{ pingErr error }
+
+func (d *pingDriver) Open(string) (driver.Conn, error) { return &pingConn{d}, nil }
+
+type pingConn struct{ d *pingDriver }
+
+func (c *pingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *pingConn) Close() error                        { return nil }
+func (c *pingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+func (c *pingConn) Ping(context.Context) error          { return c.d.pingErr }
+
+func TestPingDB(t *testing.T) {
+	drv := &pingDriver{}
+	sql.Register("health-ping", drv)
+	db, err := sql.Open("health-ping", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	check := PingDB(db)
+	assert.NoError(t, check(context.Background()))
+
+	drv.pingErr = errors.New("connection refused")
+	db.SetMaxIdleConns(0) // Force a new connection so Ping reaches the driver
+	err = check(context.Background())
+	assert.ErrorContains(t, err, "database ping failed: connection refused")
+
+	assert.Panics(t, func() { PingDB(nil) })
+}
+
+func TestPingHTTP(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	assert.NoError(t, PingHTTP(srv.URL, time.Second)(context.Background()))
+
+	status = http.StatusServiceUnavailable
+	err := PingHTTP(srv.URL, time.Second)(context.Background())
+	assert.ErrorContains(t, err, "unexpected status 503")
+
+	err = PingHTTP(srv.URL+"/slow", 20*time.Millisecond)(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The caller's deadline applies even without a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = PingHTTP(srv.URL+"/slow", 0)(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPingTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	assert.NoError(t, PingTCP(addr, time.Second)(context.Background()))
+
+	require.NoError(t, ln.Close())
+	err = PingTCP(addr, time.Second)(context.Background())
+	assert.ErrorContains(t, err, "tcp check "+addr)
+}
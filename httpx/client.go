@@ -0,0 +1,229 @@
+package httpx
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/metrics"
+)
+
+// Default client settings used by NewClient for zero-valued ClientOptions fields.
+const (
+	DefaultTimeout               = 30 * time.Second
+	DefaultDialTimeout           = 5 * time.Second
+	DefaultResponseHeaderTimeout = 10 * time.Second
+	DefaultMaxIdleConns          = 100
+	DefaultMaxIdleConnsPerHost   = 10
+	DefaultIdleConnTimeout       = 90 * time.Second
+	DefaultRetryBackoff          = 100 * time.Millisecond
+)
+
+// Metric names recorded by clients created with a Registry.
+const (
+	clientRequestsMetric = "http_client_requests"
+	clientDurationMetric = "http_client_request_duration_ms"
+)
+
+// ClientOptions configures NewClient.
+type ClientOptions struct {
+	// Timeout limits the whole request, including retries and reading the body (default: 30s)
+	Timeout time.Duration
+	// DialTimeout limits establishing a TCP connection (default: 5s)
+	DialTimeout time.Duration
+	// ResponseHeaderTimeout limits waiting for response headers after the request is sent (default: 10s)
+	ResponseHeaderTimeout time.Duration
+	// MaxIdleConns caps idle connections across all hosts (default: 100)
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections kept per host (default: 10)
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes idle connections after this long (default: 90s)
+	IdleConnTimeout time.Duration
+
+	// MaxRetries is how many times a failed request is retried (default: 0 = no retries)
+	// Only idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE) are retried, on connection
+	// errors and 502/503/504 responses. Requests with a body must support GetBody
+	// (set automatically by http.NewRequest for in-memory bodies).
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for each further retry (default: 100ms)
+	RetryBackoff time.Duration
+
+	// RequestIDHeader is the header used to propagate the request ID from contextx (default: X-Request-ID)
+	RequestIDHeader string
+
+	// Registry records http_client_requests{method,host,status} and
+	// http_client_request_duration_ms{method,host} for every attempt (optional)
+	// Failed attempts without a response are recorded with status="error".
+	Registry *metrics.Registry
+
+	// Transport is the base transport (default: a new http.Transport built from the options above)
+	// When set, the dial, pooling, and response header options are ignored.
+	Transport http.RoundTripper
+}
+
+// NewClient returns an http.Client with timeouts, connection pooling, optional retries,
+// request ID propagation (see RequestIDTransport), and optional metrics.
+//
+// Example usage:
+//
+//	client := httpx.NewClient(httpx.ClientOptions{
+//	    Timeout:    5 * time.Second,
+//	    MaxRetries: 2,
+//	    Registry:   reg,
+//	})
+//
+//	req, _ := http.NewRequestWithContext(c.UserContext(), http.MethodGet, url, nil)
+//	resp, err := client.Do(req)
+func NewClient(opts ClientOptions) *http.Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = DefaultRetryBackoff
+	}
+
+	base := opts.Transport
+	if base == nil {
+		base = newTransport(opts)
+	}
+
+	var rt http.RoundTripper = &RequestIDTransport{Next: base, Header: opts.RequestIDHeader}
+	if opts.Registry != nil {
+		rt = &metricsTransport{next: rt, reg: opts.Registry}
+	}
+	if opts.MaxRetries > 0 {
+		rt = &retryTransport{next: rt, maxRetries: opts.MaxRetries, backoff: opts.RetryBackoff}
+	}
+
+	return &http.Client{Transport: rt, Timeout: opts.Timeout}
+}
+
+// newTransport builds a pooled http.Transport from opts.
+func newTransport(opts ClientOptions) *http.Transport {
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = DefaultDialTimeout
+	}
+	if opts.ResponseHeaderTimeout <= 0 {
+		opts.ResponseHeaderTimeout = DefaultResponseHeaderTimeout
+	}
+	if opts.MaxIdleConns <= 0 {
+		opts.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if opts.MaxIdleConnsPerHost <= 0 {
+		opts.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if opts.IdleConnTimeout <= 0 {
+		opts.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: opts.DialTimeout, KeepAlive: 30 * time.Second}).DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   opts.DialTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// metricsTransport records request count and duration for each round trip.
+type metricsTransport struct {
+	next http.RoundTripper
+	reg  *metrics.Registry
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	t.reg.IncLabeled(clientRequestsMetric, map[string]string{
+		"method": req.Method,
+		"host":   req.URL.Host,
+		"status": status,
+	})
+	t.reg.ObserveLabeled(clientDurationMetric, map[string]string{
+		"method": req.Method,
+		"host":   req.URL.Host,
+	}, time.Since(start).Milliseconds())
+
+	return resp, err
+}
+
+// retryTransport retries idempotent requests on connection errors and gateway failures.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isRetryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	backoff := t.backoff
+	for attempt := 0; ; attempt++ {
+		out := req
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out = req.Clone(req.Context())
+			out.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(out)
+		if attempt >= t.maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// isRetryable reports whether req may be sent more than once.
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// shouldRetry reports whether an attempt failed in a way worth retrying.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// Don't retry once the caller gave up
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+// Package httpx provides helpers for outbound HTTP calls.
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+)
+
+// DefaultRequestIDHeader is the header used to propagate request IDs to downstream services.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDTransport is an http.RoundTripper that sets the request ID from the
+// request's context (contextx.RequestID) on every outbound request, so traces
+// can be correlated across service hops. Requests that already carry the header,
+// or whose context has no request ID, are sent unchanged.
+type RequestIDTransport struct {
+	// Next is the underlying transport (default: http.DefaultTransport)
+	Next http.RoundTripper
+	// Header is the header to set (default: X-Request-ID)
+	Header string
+}
+
+// NewRequestIDTransport wraps next with request ID propagation using the default header.
+// A nil next uses http.DefaultTransport.
+//
+// Example usage:
+//
+//	client := &http.Client{
+//	    Transport: httpx.NewRequestIDTransport(nil),
+//	    Timeout:   10 * time.Second,
+//	}
+//
+//	// In a handler, after middleware.ContextBridge()
+//	req, _ := http.NewRequestWithContext(c.UserContext(), http.MethodGet, url, nil)
+//	resp, err := client.Do(req) // X-Request-ID is set automatically
+func NewRequestIDTransport(next http.RoundTripper) *RequestIDTransport {
+	return &RequestIDTransport{Next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+// The request is cloned before the header is added, as RoundTrippers must not modify it.
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	header := t.Header
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	rid, ok := contextx.RequestID(req.Context())
+	if !ok || req.Header.Get(header) != "" {
+		return next.RoundTrip(req)
+	}
+
+	out := req.Clone(req.Context())
+	out.Header.Set(header, rid)
+	return next.RoundTrip(out)
+}
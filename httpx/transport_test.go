@@ -0,0 +1,52 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDTransport(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(DefaultRequestIDHeader)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRequestIDTransport(nil)}
+
+	ctx := contextx.WithRequestID(context.Background(), "rid-42")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "rid-42", got)
+	assert.Empty(t, req.Header.Get(DefaultRequestIDHeader), "original request must not be modified")
+}
+
+func TestRequestIDTransportKeepsExistingHeader(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Correlation-ID")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &RequestIDTransport{Header: "X-Correlation-ID"}}
+
+	ctx := contextx.WithRequestID(context.Background(), "rid-42")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Correlation-ID", "explicit")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "explicit", got)
+}
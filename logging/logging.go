@@ -2,8 +2,15 @@ package logging
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"syscall"
 
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/cubetiqlabs/gopkg/metrics"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -11,8 +18,69 @@ import (
 var (
 	logger *zap.Logger
 	once   sync.Once
+	sugar  atomic.Pointer[sugared] // Sugared global logger, derived on first use of S
 )
 
+// sugared caches the sugared form of base.
+type sugared struct {
+	base *zap.Logger
+	s    *zap.SugaredLogger
+}
+
+// Options configures the global logger built by InitWithOptions.
+type Options struct {
+	// Level is the minimum log level: debug, info, warn, error, dpanic, panic, fatal (default: "info")
+	Level string
+	// Development enables development mode with stack traces and DPanic (default: false)
+	Development bool
+	// OutputPaths receive every entry at or above Level (default: ["stderr"])
+	OutputPaths []string
+	// ErrorOutputPaths additionally receive warn, error, and fatal entries (default: nil)
+	// Entries are duplicated, so the main outputs still see them. Unlike zap.Config's
+	// field of the same name, these are for application logs, not zap's internal errors.
+	ErrorOutputPaths []string
+	// TimeFormat selects how the "ts" field is encoded (default: TimeISO8601)
+	TimeFormat TimeFormat
+	// ZapOptions are applied when building the logger, after the package's own options (default: nil)
+	// e.g. zap.AddCallerSkip(1) for a facade wrapping the package-level helpers,
+	// or zap.Hooks(...) / CountErrors to observe entries.
+	ZapOptions []zap.Option
+	// Metrics counts warn and higher entries in log_messages_total{level="..."} (default: nil)
+	// See CountLevels.
+	Metrics *metrics.Registry
+}
+
+// TimeFormat selects the encoding of log timestamps.
+type TimeFormat string
+
+// Supported timestamp encodings.
+const (
+	// TimeISO8601 encodes timestamps as ISO8601 strings with millisecond precision (default)
+	TimeISO8601 TimeFormat = "iso8601"
+	// TimeRFC3339Nano encodes timestamps as RFC3339 strings with nanosecond precision
+	TimeRFC3339Nano TimeFormat = "rfc3339nano"
+	// TimeEpochMillis encodes timestamps as float milliseconds since the Unix epoch
+	TimeEpochMillis TimeFormat = "epoch_millis"
+	// TimeEpochSeconds encodes timestamps as float seconds since the Unix epoch
+	TimeEpochSeconds TimeFormat = "epoch_seconds"
+)
+
+// timeEncoder returns the zapcore encoder for f. Unknown formats are an error.
+func (f TimeFormat) timeEncoder() (zapcore.TimeEncoder, error) {
+	switch f {
+	case "", TimeISO8601:
+		return zapcore.ISO8601TimeEncoder, nil
+	case TimeRFC3339Nano:
+		return zapcore.RFC3339NanoTimeEncoder, nil
+	case TimeEpochMillis:
+		return zapcore.EpochMillisTimeEncoder, nil
+	case TimeEpochSeconds:
+		return zapcore.EpochTimeEncoder, nil
+	default:
+		return nil, fmt.Errorf("logging: unknown time format %q", string(f))
+	}
+}
+
 // Init initializes a global zap logger. Safe to call multiple times; first call wins.
 //
 // Parameters:
@@ -25,37 +93,191 @@ var (
 //	if err != nil {
 //	    panic(err)
 //	}
-//	defer logger.Sync()
+//	defer logging.Sync()
 func Init(level string, development bool) (*zap.Logger, error) {
+	return InitWithOptions(Options{Level: level, Development: development})
+}
+
+// InitWithOptions initializes the global zap logger with custom outputs.
+// Shares Init's once semantics: whichever is called first wins.
+//
+// Example usage:
+//
+//	logger, err := logging.InitWithOptions(logging.Options{
+//	    Level:            "info",
+//	    OutputPaths:      []string{"stdout"},
+//	    ErrorOutputPaths: []string{"/var/log/app/errors.log"}, // warn and above only
+//	    TimeFormat:       logging.TimeEpochMillis,                 // numeric "ts" for ingestion
+//	    ZapOptions: []zap.Option{
+//	        zap.AddCallerSkip(1),            // report the facade's caller
+//	        logging.CountErrors(logErrors), // *metrics.Counter of error-level entries
+//	    },
+//	    Metrics: reg, // log_messages_total{level="warn"|"error"|...}
+//	})
+//	if err != nil {
+//	    panic(err)
+//	}
+//	defer logging.Sync() // flushes both outputs
+func InitWithOptions(opts Options) (*zap.Logger, error) {
 	var err error
+	once.Do(func() {
+		logger, err = build(opts)
+	})
+	return logger, err
+}
+
+// build constructs a logger from opts. When ErrorOutputPaths are set, a second
+// core filtered to >= warn is teed with the main core, so Sync flushes both.
+func build(opts Options) (*zap.Logger, error) {
+	if len(opts.OutputPaths) == 0 {
+		opts.OutputPaths = []string{"stderr"}
+	}
+
+	encodeTime, err := opts.TimeFormat.timeEncoder()
+	if err != nil {
+		return nil, err
+	}
+
+	level := zap.NewAtomicLevelAt(parseLevel(opts.Level))
+	encCfg := encoderConfig(opts.Development)
+	encCfg.EncodeTime = encodeTime
+
+	cfg := zap.Config{
+		Level:            level,
+		Development:      opts.Development,
+		Encoding:         "json",
+		EncoderConfig:    encCfg,
+		OutputPaths:      opts.OutputPaths,
+		ErrorOutputPaths: []string{"stderr"},
+	}
+
+	var zapOpts []zap.Option
+	if opts.Metrics != nil {
+		zapOpts = append(zapOpts, CountLevels(opts.Metrics))
+	}
+	if len(opts.ErrorOutputPaths) > 0 {
+		sink, _, err := zap.Open(opts.ErrorOutputPaths...)
+		if err != nil {
+			return nil, err
+		}
+		errCore := zapcore.NewCore(
+			zapcore.NewJSONEncoder(encCfg),
+			sink,
+			zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+				return l >= zapcore.WarnLevel && level.Enabled(l)
+			}),
+		)
+		zapOpts = append(zapOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, errCore)
+		}))
+	}
+
+	return cfg.Build(append(zapOpts, opts.ZapOptions...)...)
+}
+
+// CountErrors returns a zap option that increments counter for every entry logged
+// at error level or above (error, dpanic, panic, fatal). Pass it via
+// Options.ZapOptions. Only entries that pass the level check are counted.
+//
+// Example usage:
+//
+//	logErrors := &metrics.Counter{}
+//	logging.InitWithOptions(logging.Options{
+//	    ZapOptions: []zap.Option{logging.CountErrors(logErrors)},
+//	})
+func CountErrors(counter *metrics.Counter) zap.Option {
+	return zap.Hooks(func(entry zapcore.Entry) error {
+		if entry.Level >= zapcore.ErrorLevel {
+			counter.Inc()
+		}
+		return nil
+	})
+}
+
+// logMessagesMetric counts log entries by level, see CountLevels.
+const logMessagesMetric = "log_messages_total"
+
+// CountLevels returns a zap option that counts entries logged at warn level or above
+// in reg as log_messages_total{level="warn|error|dpanic|panic|fatal"}. The series are
+// created up front, so they are rendered (as 0) before the first entry and the hook
+// doesn't allocate. Pass it via Options.ZapOptions, or set Options.Metrics.
+//
+// Example usage:
+//
+//	logger := zap.New(core, logging.CountLevels(reg))
+func CountLevels(reg *metrics.Registry) zap.Option {
+	var counters [zapcore.FatalLevel - zapcore.WarnLevel + 1]*metrics.Counter
+	for l := zapcore.WarnLevel; l <= zapcore.FatalLevel; l++ {
+		counters[l-zapcore.WarnLevel] = reg.LabeledCounter(logMessagesMetric, map[string]string{"level": l.String()})
+	}
+
+	return zap.Hooks(func(entry zapcore.Entry) error {
+		if entry.Level < zapcore.WarnLevel || entry.Level > zapcore.FatalLevel {
+			return nil
+		}
+		if c := counters[entry.Level-zapcore.WarnLevel]; c != nil {
+			c.Inc()
+		}
+		return nil
+	})
+}
+
+// encoderConfig returns the JSON encoder settings shared by all outputs.
+func encoderConfig(development bool) zapcore.EncoderConfig {
 	var stackKey string
 	if development {
 		stackKey = "stack"
 	}
 
-	once.Do(func() {
-		cfg := zap.Config{
-			Level:       zap.NewAtomicLevelAt(parseLevel(level)),
-			Development: development,
-			Encoding:    "json",
-			EncoderConfig: zapcore.EncoderConfig{
-				TimeKey:       "ts",
-				LevelKey:      "level",
-				NameKey:       "logger",
-				CallerKey:     "caller",
-				MessageKey:    "msg",
-				StacktraceKey: stackKey,
-				EncodeTime:    zapcore.ISO8601TimeEncoder,
-				EncodeLevel:   zapcore.LowercaseLevelEncoder,
-				EncodeCaller:  zapcore.ShortCallerEncoder,
-			},
-			OutputPaths:      []string{"stderr"},
-			ErrorOutputPaths: []string{"stderr"},
+	return zapcore.EncoderConfig{
+		TimeKey:       "ts",
+		LevelKey:      "level",
+		NameKey:       "logger",
+		CallerKey:     "caller",
+		MessageKey:    "msg",
+		StacktraceKey: stackKey,
+		EncodeTime:    zapcore.ISO8601TimeEncoder,
+		EncodeLevel:   zapcore.LowercaseLevelEncoder,
+		EncodeCaller:  zapcore.ShortCallerEncoder,
+	}
+}
+
+// Sync flushes the global logger, ignoring the benign errors returned when syncing
+// stderr/stdout attached to a terminal or pipe ("sync /dev/stderr: invalid argument").
+// Real flush failures are returned. Safe to call when the logger is not initialized.
+//
+// Example usage:
+//
+//	logger, err := logging.Init("info", false)
+//	if err != nil {
+//	    panic(err)
+//	}
+//	defer logging.Sync()
+func Sync() error {
+	if logger == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, err := range multierr.Errors(logger.Sync()) {
+		if !isBenignSyncError(err) {
+			errs = append(errs, err)
 		}
-		logger, err = cfg.Build()
-	})
+	}
+	return multierr.Combine(errs...)
+}
 
-	return logger, err
+// MustSync is like Sync but panics on real flush errors. Useful in tests.
+func MustSync() {
+	if err := Sync(); err != nil {
+		panic(err)
+	}
+}
+
+// isBenignSyncError reports whether err is the harmless error returned by
+// fsync on non-file outputs such as terminals and pipes.
+func isBenignSyncError(err error) bool {
+	return errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EBADF)
 }
 
 // parseLevel converts a string level to zapcore.Level.
@@ -87,6 +309,58 @@ func L() *zap.Logger {
 	return logger
 }
 
+// Global returns the global logger, or false if Init has not been called. Unlike L
+// it never panics, so libraries can fall back to the global logger when it exists.
+//
+// Example:
+//
+//	if lg, ok := logging.Global(); ok {
+//	    lg.Info("plugin loaded")
+//	}
+func Global() (*zap.Logger, bool) {
+	return logger, logger != nil
+}
+
+// S returns the global logger's sugared form, for printf-style and loosely typed
+// key-value logging. It is derived on first use and cached. Panics if not initialized.
+//
+// Example:
+//
+//	logging.S().Infof("loaded %d plugins", n)
+//	logging.S().Infow("cache miss", "key", key)
+func S() *zap.SugaredLogger {
+	base := L()
+	if cached := sugar.Load(); cached != nil && cached.base == base {
+		return cached.s
+	}
+	s := base.Sugar()
+	sugar.Store(&sugared{base: base, s: s})
+	return s
+}
+
+// SFromContext is the sugared counterpart to FromContext. A logger stored in ctx
+// (WithContext, WithLogger) is used as is, since it is already request-scoped;
+// otherwise the global logger is enriched with ContextFields (request_id, tenant,
+// app, user); when ctx carries none of them the cached S() is returned.
+//
+// Example:
+//
+//	func handleOrder(ctx context.Context, id string) {
+//	    logging.SFromContext(ctx).Infof("processing order %s", id)
+//	}
+func SFromContext(ctx context.Context) *zap.SugaredLogger {
+	if v := ctx.Value(ctxKeyLogger{}); v != nil {
+		if lg, ok := v.(*zap.Logger); ok {
+			return lg.Sugar()
+		}
+	}
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return S()
+	}
+	return L().With(fields...).Sugar()
+}
+
 // WithContext stores logger with fields inside context.
 // This is useful for adding request-scoped fields to logs.
 //
@@ -98,6 +372,12 @@ func WithContext(ctx context.Context, fields ...zap.Field) context.Context {
 	return context.WithValue(ctx, ctxKeyLogger{}, L().With(fields...))
 }
 
+// WithLogger stores the given logger inside context.
+// Use this when a request-scoped logger is built outside the global logger.
+func WithLogger(ctx context.Context, lg *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKeyLogger{}, lg)
+}
+
 // FromContext extracts logger from context or returns global logger.
 // This allows request-scoped logging without passing logger explicitly.
 //
@@ -117,6 +397,29 @@ func FromContext(ctx context.Context) *zap.Logger {
 
 type ctxKeyLogger struct{}
 
+// ContextFields returns zap fields for the contextx values present in ctx:
+// request_id, tenant, app, and user, in that order. Absent values are omitted.
+// It is the zap-typed counterpart to contextx.Fields.
+//
+// Example:
+//
+//	logging.Info("order processed", logging.ContextFields(ctx)...)
+//	logging.Info("order processed", append(logging.ContextFields(ctx), zap.String("order_id", id))...)
+func ContextFields(ctx context.Context) []zap.Field {
+	values := contextx.Fields(ctx)
+	fields := make([]zap.Field, 0, len(values)+1)
+
+	if rid, ok := contextx.RequestID(ctx); ok {
+		fields = append(fields, zap.String("request_id", rid))
+	}
+	for _, key := range []string{"tenant", "app", "user"} {
+		if v, ok := values[key]; ok {
+			fields = append(fields, zap.String(key, v))
+		}
+	}
+	return fields
+}
+
 // Info logs an info message
 func Info(msg string, fields ...zap.Field) {
 	if logger != nil {
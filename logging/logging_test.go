@@ -0,0 +1,254 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/contextx"
+	"github.com/cubetiqlabs/gopkg/metrics"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestIsBenignSyncError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"einval", &os.PathError{Op: "sync", Path: "/dev/stderr", Err: syscall.EINVAL}, true},
+		{"enotty", &os.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.ENOTTY}, true},
+		{"real error", &os.PathError{Op: "sync", Path: "/var/log/app.log", Err: syscall.EIO}, false},
+		{"plain error", errors.New("disk full"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBenignSyncError(tt.err); got != tt.want {
+				t.Fatalf("isBenignSyncError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncWithoutLogger(t *testing.T) {
+	if err := Sync(); err != nil {
+		t.Fatalf("expected nil error without logger, got %v", err)
+	}
+}
+
+func TestBuildRoutesWarnToErrorOutput(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "app.log")
+	errPath := filepath.Join(dir, "errors.log")
+
+	lg, err := build(Options{
+		Level:            "debug",
+		OutputPaths:      []string{mainPath},
+		ErrorOutputPaths: []string{errPath},
+	})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	lg.Debug("debug entry")
+	lg.Info("info entry")
+	lg.Warn("warn entry")
+	lg.Error("error entry")
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	mainLog, _ := os.ReadFile(mainPath)
+	errLog, _ := os.ReadFile(errPath)
+
+	for _, msg := range []string{"debug entry", "info entry", "warn entry", "error entry"} {
+		if !strings.Contains(string(mainLog), msg) {
+			t.Fatalf("expected main output to contain %q, got:\n%s", msg, mainLog)
+		}
+	}
+	if strings.Contains(string(errLog), "info entry") || strings.Contains(string(errLog), "debug entry") {
+		t.Fatalf("expected error output to exclude info/debug, got:\n%s", errLog)
+	}
+	if !strings.Contains(string(errLog), "warn entry") || !strings.Contains(string(errLog), "error entry") {
+		t.Fatalf("expected error output to contain warn and error, got:\n%s", errLog)
+	}
+}
+
+func TestBuildTimeFormat(t *testing.T) {
+	tests := []struct {
+		format TimeFormat
+		check  func(ts interface{}) bool
+	}{
+		{"", func(ts interface{}) bool { s, ok := ts.(string); return ok && strings.Contains(s, "T") }},
+		{TimeRFC3339Nano, func(ts interface{}) bool { s, ok := ts.(string); return ok && strings.HasSuffix(s, "Z") }},
+		{TimeEpochMillis, func(ts interface{}) bool { f, ok := ts.(float64); return ok && f > 1e12 }},
+		{TimeEpochSeconds, func(ts interface{}) bool { f, ok := ts.(float64); return ok && f > 1e9 && f < 1e11 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			lg, err := build(Options{OutputPaths: []string{path}, TimeFormat: tt.format})
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			lg.Info("entry")
+			_ = lg.Sync()
+
+			data, _ := os.ReadFile(path)
+			var entry map[string]interface{}
+			if err := json.Unmarshal(data, &entry); err != nil {
+				t.Fatalf("unmarshal %q: %v", data, err)
+			}
+			if !tt.check(entry["ts"]) {
+				t.Fatalf("unexpected ts %v for format %q", entry["ts"], tt.format)
+			}
+		})
+	}
+}
+
+// logViaFacade stands in for an application facade wrapping the logger.
+func logViaFacade(lg *zap.Logger, msg string) {
+	lg.Error(msg)
+}
+
+func TestBuildZapOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	errCount := &metrics.Counter{}
+
+	lg, err := build(Options{
+		OutputPaths: []string{path},
+		ZapOptions:  []zap.Option{zap.AddCallerSkip(1), CountErrors(errCount)},
+	})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	_, _, line, _ := runtime.Caller(0)
+	logViaFacade(lg, "failed") // caller should point here, at line+1
+	lg.Warn("not counted")
+	_ = lg.Sync()
+
+	data, _ := os.ReadFile(path)
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.SplitN(string(data), "\n", 2)[0]), &entry); err != nil {
+		t.Fatalf("unmarshal %q: %v", data, err)
+	}
+	if caller, _ := entry["caller"].(string); !strings.HasSuffix(caller, "logging_test.go:"+strconv.Itoa(line+1)) {
+		t.Fatalf("expected caller to skip the facade, got %q", caller)
+	}
+	if got := errCount.Get(); got != 1 {
+		t.Fatalf("expected 1 counted error, got %d", got)
+	}
+}
+
+func TestBuildMetricsCountsLevels(t *testing.T) {
+	reg := metrics.NewRegistry()
+	lg, err := build(Options{Level: "debug", OutputPaths: []string{filepath.Join(t.TempDir(), "app.log")}, Metrics: reg})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	if !strings.Contains(reg.RenderPrometheus(), `log_messages_total{level="fatal"} 0`) {
+		t.Fatal("expected series to exist before the first entry")
+	}
+
+	lg.Info("ignored")
+	lg.Warn("w")
+	lg.Error("e1")
+	lg.Error("e2")
+
+	for level, want := range map[string]uint64{"warn": 1, "error": 2, "dpanic": 0} {
+		if got, _ := reg.LabeledValue("log_messages_total", map[string]string{"level": level}); got != want {
+			t.Fatalf("log_messages_total{level=%q} = %d, want %d", level, got, want)
+		}
+	}
+	if _, ok := reg.LabeledValue("log_messages_total", map[string]string{"level": "info"}); ok {
+		t.Fatal("expected info entries not to be counted")
+	}
+}
+
+func TestBuildUnknownTimeFormat(t *testing.T) {
+	if _, err := build(Options{TimeFormat: "unix"}); err == nil {
+		t.Fatal("expected error for unknown time format")
+	}
+}
+
+func TestContextFields(t *testing.T) {
+	ctx := contextx.WithRequestID(context.Background(), "rid-1")
+	ctx = contextx.WithTenant(ctx, "t1")
+	ctx = contextx.WithUser(ctx, "u1")
+
+	fields := ContextFields(ctx)
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, f.Key+"="+f.String)
+	}
+	if got := strings.Join(keys, ","); got != "request_id=rid-1,tenant=t1,user=u1" {
+		t.Fatalf("unexpected fields: %s", got)
+	}
+
+	if fields := ContextFields(context.Background()); len(fields) != 0 {
+		t.Fatalf("expected no fields for empty context, got %d", len(fields))
+	}
+}
+
+func TestSugaredLoggers(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	prev := logger
+	logger = zap.New(core)
+	t.Cleanup(func() { logger = prev })
+
+	if S() != S() {
+		t.Fatal("expected S to return the cached sugared logger")
+	}
+
+	ctx := contextx.WithRequestID(context.Background(), "rid-1")
+	ctx = contextx.WithTenant(ctx, "t1")
+	SFromContext(ctx).Infof("order %d", 42)
+	SFromContext(context.Background()).Info("plain")
+
+	// A stored logger is used without adding the context fields again
+	scoped := WithLogger(ctx, zap.New(core).With(zap.String("request_id", "rid-1")))
+	SFromContext(scoped).Infow("scoped", "k", "v")
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if got := entries[0].Message; got != "order 42" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if got := entries[0].ContextMap(); got["request_id"] != "rid-1" || got["tenant"] != "t1" {
+		t.Fatalf("unexpected fields: %v", got)
+	}
+	if got := len(entries[1].Context); got != 0 {
+		t.Fatalf("expected no fields without context values, got %d", got)
+	}
+	if got := len(entries[2].Context); got != 2 {
+		t.Fatalf("expected request_id and k only, got %v", entries[2].ContextMap())
+	}
+}
+
+func TestGlobal(t *testing.T) {
+	prev := logger
+	t.Cleanup(func() { logger = prev })
+
+	logger = nil
+	if lg, ok := Global(); ok || lg != nil {
+		t.Fatal("expected no global logger before Init")
+	}
+
+	logger = zap.NewNop()
+	if lg, ok := Global(); !ok || lg != logger {
+		t.Fatal("expected the global logger")
+	}
+}
@@ -0,0 +1,28 @@
+// Package logtest provides helpers for asserting on log output in tests.
+// It lives in its own package so production code importing logging does not
+// link the zap observer.
+package logtest
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// NewObserver returns a logger that records entries at or above level in memory.
+// Unknown levels default to info, matching logging.Init.
+//
+// Example:
+//
+//	logger, logs := logtest.NewObserver("debug")
+//	app.Use(middleware.AccessLogWithConfig(&middleware.AccessLogConfig{Logger: logger}))
+//	// ... perform request ...
+//	entries := logs.FilterMessage("http request").All()
+func NewObserver(level string) (*zap.Logger, *observer.ObservedLogs) {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		lvl = zapcore.InfoLevel
+	}
+	core, logs := observer.New(lvl)
+	return zap.New(core), logs
+}
@@ -0,0 +1,33 @@
+package logtest
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewObserverRecordsEntries(t *testing.T) {
+	logger, logs := NewObserver("warn")
+
+	logger.Info("ignored")
+	logger.Warn("recorded", zap.String("key", "value"))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Message != "recorded" || entries[0].ContextMap()["key"] != "value" {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+}
+
+func TestNewObserverUnknownLevelDefaultsToInfo(t *testing.T) {
+	logger, logs := NewObserver("verbose")
+
+	logger.Debug("ignored")
+	logger.Info("recorded")
+
+	if logs.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", logs.Len())
+	}
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import "fmt"
+
+// Bucket presets for histograms with explicit upper bounds. Each slice is sorted in
+// increasing order; treat them as read-only and copy before modifying.
+var (
+	// DefaultLatencyBucketsMs covers request latencies from 5ms to 10s, suitable for
+	// most HTTP and gRPC handlers.
+	DefaultLatencyBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+	// DefaultSizeBucketsBytes covers payload sizes from 256B to 4MiB in powers of 4.
+	DefaultSizeBucketsBytes = ExponentialBuckets(256, 4, 8)
+)
+
+// ExponentialBuckets returns count bucket upper bounds, the first being start and
+// each following one factor times the previous. Like the Prometheus client, it
+// panics if count < 1, start <= 0, or factor <= 1.
+//
+// Example:
+//
+//	metrics.ExponentialBuckets(1, 2, 5) // [1 2 4 8 16]
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	if count < 1 {
+		panic(fmt.Sprintf("metrics: ExponentialBuckets needs a positive count, got %d", count))
+	}
+	if start <= 0 {
+		panic(fmt.Sprintf("metrics: ExponentialBuckets needs a positive start, got %g", start))
+	}
+	if factor <= 1 {
+		panic(fmt.Sprintf("metrics: ExponentialBuckets needs a factor greater than 1, got %g", factor))
+	}
+
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// LinearBuckets returns count bucket upper bounds, the first being start and each
+// following one width larger than the previous. Like the Prometheus client, it
+// panics if count < 1 or width <= 0.
+//
+// Example:
+//
+//	metrics.LinearBuckets(100, 100, 4) // [100 200 300 400]
+func LinearBuckets(start, width float64, count int) []float64 {
+	if count < 1 {
+		panic(fmt.Sprintf("metrics: LinearBuckets needs a positive count, got %d", count))
+	}
+	if width <= 0 {
+		panic(fmt.Sprintf("metrics: LinearBuckets needs a positive width, got %g", width))
+	}
+
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start += width
+	}
+	return buckets
+}
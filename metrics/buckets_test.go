@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBuckets(t *testing.T) {
+	assert.Equal(t, []float64{1, 2, 4, 8, 16}, ExponentialBuckets(1, 2, 5))
+	assert.Panics(t, func() { ExponentialBuckets(0, 2, 5) })
+	assert.Panics(t, func() { ExponentialBuckets(1, 1, 5) })
+	assert.Panics(t, func() { ExponentialBuckets(1, 2, 0) })
+}
+
+func TestLinearBuckets(t *testing.T) {
+	assert.Equal(t, []float64{100, 200, 300, 400}, LinearBuckets(100, 100, 4))
+	assert.Equal(t, []float64{-1, 0.5, 2}, LinearBuckets(-1, 1.5, 3))
+	assert.Panics(t, func() { LinearBuckets(0, 0, 3) })
+	assert.Panics(t, func() { LinearBuckets(0, 1, 0) })
+}
+
+func TestBucketPresets(t *testing.T) {
+	for _, buckets := range [][]float64{DefaultLatencyBucketsMs, DefaultSizeBucketsBytes} {
+		assert.True(t, sort.Float64sAreSorted(buckets))
+	}
+	assert.Equal(t, float64(256), DefaultSizeBucketsBytes[0])
+	assert.Equal(t, float64(4<<20), DefaultSizeBucketsBytes[len(DefaultSizeBucketsBytes)-1])
+}
+
+func TestHistogram_Buckets(t *testing.T) {
+	h := NewHistogram([]float64{10, 100})
+	h.Observe(5)
+	h.Observe(10)
+	h.Observe(50)
+	h.Observe(500)
+
+	bounds, cumulative := h.Buckets()
+	assert.Equal(t, []float64{10, 100, math.Inf(1)}, bounds)
+	assert.Equal(t, []uint64{2, 3, 4}, cumulative)
+	assert.Equal(t, uint64(4), h.Count())
+
+	h.Reset()
+	bounds, cumulative = h.Buckets()
+	assert.Equal(t, []float64{10, 100, math.Inf(1)}, bounds)
+	assert.Equal(t, []uint64{0, 0, 0}, cumulative)
+
+	bounds, cumulative = (&Histogram{}).Buckets()
+	assert.Nil(t, bounds)
+	assert.Nil(t, cumulative)
+}
+
+func TestHistogram_SetBuckets(t *testing.T) {
+	h := NewHistogram([]float64{1, 2, math.Inf(1)})
+	assert.True(t, h.SetBuckets([]float64{1, 2}), "same buckets are a no-op")
+	assert.False(t, h.SetBuckets([]float64{1, 3}))
+	bounds, _ := h.Buckets()
+	assert.Equal(t, []float64{1, 2, math.Inf(1)}, bounds)
+
+	assert.Panics(t, func() { NewHistogram(nil) })
+	assert.Panics(t, func() { NewHistogram([]float64{2, 1}) })
+	assert.Panics(t, func() { NewHistogram([]float64{1, 1}) })
+
+	observed := &Histogram{}
+	observed.Observe(1)
+	assert.False(t, observed.SetBuckets([]float64{1}))
+	bounds, _ = observed.Buckets()
+	assert.Nil(t, bounds)
+}
+
+func TestRegistry_SetHistogramBuckets(t *testing.T) {
+	reg := NewRegistry()
+	reg.SetHistogramBuckets("http_response_bytes", []float64{100, 1000})
+	reg.ObserveLabeled("http_response_bytes", map[string]string{"path": "/a"}, 100)
+	reg.ObserveLabeled("http_response_bytes", map[string]string{"path": "/a"}, 5000)
+
+	prom := reg.RenderPrometheus()
+	assert.Contains(t, prom, "http_response_bytes_bucket{path=\"/a\",le=\"100\"} 1\n")
+	assert.Contains(t, prom, "http_response_bytes_bucket{path=\"/a\",le=\"1000\"} 1\n")
+	assert.Contains(t, prom, "http_response_bytes_bucket{path=\"/a\",le=\"+Inf\"} 2\n")
+
+	om := reg.RenderOpenMetrics()
+	assert.Contains(t, om, "# TYPE http_response_bytes histogram\nhttp_response_bytes_bucket{path=\"/a\",le=\"100\"} 1\n")
+	assert.Contains(t, om, "http_response_bytes_bucket{path=\"/a\",le=\"+Inf\"} 2\nhttp_response_bytes_sum{path=\"/a\"} 5100\nhttp_response_bytes_count{path=\"/a\"} 2\n")
+
+	assert.True(t, reg.SetHistogramBuckets("http_response_bytes", []float64{100, 1000}))
+	assert.False(t, reg.SetHistogramBuckets("http_response_bytes", []float64{10}))
+	reg.ObserveLabeled("queue_wait_ms", nil, 1)
+	assert.False(t, reg.SetHistogramBuckets("queue_wait_ms", []float64{10}), "series already has observations")
+	assert.NotContains(t, reg.RenderPrometheus(), "queue_wait_ms_bucket")
+	reg.IncLabeled("jobs", nil)
+	assert.Panics(t, func() { reg.SetHistogramBuckets("jobs", []float64{10}) })
+
+	// Reset keeps buckets
+	reg.Reset()
+	reg.ObserveLabeled("http_response_bytes", map[string]string{"path": "/b"}, 1)
+	assert.Contains(t, reg.RenderPrometheus(), "http_response_bytes_bucket{path=\"/b\",le=\"100\"} 1\n")
+}
+
+func TestRegistry_RequestDurationBuckets(t *testing.T) {
+	reg := NewRegistry()
+	assert.NotContains(t, reg.RenderPrometheus(), "http_request_duration_ms_bucket")
+	assert.Contains(t, reg.RenderOpenMetrics(), "# TYPE http_request_duration_ms summary\n")
+
+	reg.RequestDuration.SetBuckets(DefaultLatencyBucketsMs)
+	reg.RequestDuration.Observe(30)
+
+	prom := reg.RenderPrometheus()
+	assert.Contains(t, prom, "http_request_duration_ms_bucket{le=\"25\"} 0\n")
+	assert.Contains(t, prom, "http_request_duration_ms_bucket{le=\"50\"} 1\n")
+	assert.Contains(t, prom, "http_request_duration_ms_bucket{le=\"+Inf\"} 1\n")
+	assert.Contains(t, reg.RenderOpenMetrics(), "# TYPE http_request_duration_ms histogram\nhttp_request_duration_ms_bucket{le=\"5\"} 0\n")
+
+	reg.Reset()
+	bounds, _ := reg.RequestDuration.Buckets()
+	assert.Len(t, bounds, len(DefaultLatencyBucketsMs)+1)
+}
@@ -1,8 +1,13 @@
 package metrics
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,15 +34,159 @@ func (c *Counter) Get() uint64 {
 	return atomic.LoadUint64(&c.v)
 }
 
-// Histogram tracks a distribution of values (simple sum + count for average).
-// Can be extended with buckets for percentiles if needed.
+// MarshalJSON implements json.Marshaler, encoding the current value as a number,
+// so counters can be embedded directly in response structs.
+//
+// Example:
+//
+//	c.JSON(struct {
+//	    Requests *metrics.Counter `json:"requests"`
+//	}{reg.RequestsTotal}) // {"requests":1042}
+func (c *Counter) MarshalJSON() ([]byte, error) {
+	return strconv.AppendUint(nil, c.Get(), 10), nil
+}
+
+// Gauge is an atomic float64 value that can go up and down.
+type Gauge struct {
+	bits uint64
+}
+
+// Set sets the gauge to value.
+func (g *Gauge) Set(value float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+// Add adds delta (which may be negative) to the gauge.
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&g.bits, old, next) {
+			return
+		}
+	}
+}
+
+// Inc increments the gauge by 1.
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec decrements the gauge by 1.
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// Get returns the current gauge value.
+func (g *Gauge) Get() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Histogram tracks a distribution of values: sum and count for the average and,
+// once SetBuckets has been called, cumulative counts per bucket upper bound.
+// The zero value has no buckets.
 type Histogram struct {
-	sum   uint64
-	count uint64
+	sum     uint64
+	count   uint64
+	buckets atomic.Pointer[histogramBuckets] // Nil until SetBuckets
+}
+
+// histogramBuckets holds the bucket upper bounds of a Histogram and the number of
+// observations per bucket (not cumulative). counts has one extra slot for +Inf.
+type histogramBuckets struct {
+	bounds []float64
+	counts []uint64
+}
+
+// NewHistogram returns a Histogram with the given bucket upper bounds.
+// It panics on invalid buckets, see validBuckets.
+//
+// Example:
+//
+//	h := metrics.NewHistogram(metrics.DefaultLatencyBucketsMs)
+//	h.Observe(42) // Counted in the le="50" bucket and above
+func NewHistogram(buckets []float64) *Histogram {
+	h := &Histogram{}
+	h.SetBuckets(buckets)
+	return h
+}
+
+// SetBuckets sets the bucket upper bounds, rendered as _bucket series with an le
+// label, and reports whether the histogram uses them. Call it before the first
+// Observe; buckets can only be set once:
+//   - Setting the same buckets again is a no-op that returns true, so several
+//     middlewares can share a histogram
+//   - If different buckets are already set, or the histogram already has
+//     observations, nothing changes and it returns false
+//
+// Buckets must be non-empty and strictly increasing, or SetBuckets panics; a
+// trailing +Inf is implied and dropped. The slice is copied.
+func (h *Histogram) SetBuckets(buckets []float64) bool {
+	bounds := validBuckets(buckets)
+	if existing := h.buckets.Load(); existing != nil {
+		return slices.Equal(existing.bounds, bounds)
+	}
+	if h.Count() > 0 {
+		return false
+	}
+
+	hb := &histogramBuckets{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+	if h.buckets.CompareAndSwap(nil, hb) {
+		return true
+	}
+	return slices.Equal(h.buckets.Load().bounds, bounds) // Lost a race with another SetBuckets
+}
+
+// validBuckets returns a copy of buckets without a trailing +Inf, and panics if
+// they are empty or not strictly increasing.
+func validBuckets(buckets []float64) []float64 {
+	if n := len(buckets); n > 0 && math.IsInf(buckets[n-1], 1) {
+		buckets = buckets[:n-1]
+	}
+	if len(buckets) == 0 {
+		panic("metrics: histogram needs at least one bucket")
+	}
+	for i, b := range buckets {
+		if math.IsNaN(b) || (i > 0 && b <= buckets[i-1]) {
+			panic(fmt.Sprintf("metrics: histogram buckets must be strictly increasing, got %v", buckets))
+		}
+	}
+	return append([]float64(nil), buckets...)
+}
+
+// Buckets returns the bucket upper bounds, ending with +Inf, and the cumulative
+// number of observations less than or equal to each; both are nil if no buckets
+// are set. The +Inf count equals Count, apart from Observe calls in flight.
+func (h *Histogram) Buckets() (bounds []float64, cumulative []uint64) {
+	hb := h.buckets.Load()
+	if hb == nil {
+		return nil, nil
+	}
+	bounds = append(append(make([]float64, 0, len(hb.counts)), hb.bounds...), math.Inf(1))
+	cumulative = make([]uint64, len(hb.counts))
+	var total uint64
+	for i := range hb.counts {
+		total += atomic.LoadUint64(&hb.counts[i])
+		cumulative[i] = total
+	}
+	return bounds, cumulative
+}
+
+// bounds returns the bucket upper bounds, or nil if no buckets are set. The
+// result must not be modified.
+func (h *Histogram) bounds() []float64 {
+	if hb := h.buckets.Load(); hb != nil {
+		return hb.bounds
+	}
+	return nil
 }
 
 // Observe records a value in milliseconds.
 func (h *Histogram) Observe(ms int64) {
+	if hb := h.buckets.Load(); hb != nil {
+		i, _ := slices.BinarySearch(hb.bounds, float64(ms)) // First bound >= ms, or the +Inf slot
+		atomic.AddUint64(&hb.counts[i], 1)
+	}
 	atomic.AddUint64(&h.sum, uint64(ms))
 	atomic.AddUint64(&h.count, 1)
 }
@@ -62,12 +211,58 @@ func (h *Histogram) Sum() uint64 {
 	return atomic.LoadUint64(&h.sum)
 }
 
+// histogramJSON is the JSON form of a Histogram.
+type histogramJSON struct {
+	Avg   float64 `json:"avg"`
+	Count uint64  `json:"count"`
+	Sum   uint64  `json:"sum"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding the current values as
+// {"avg":..,"count":..,"sum":..}. The average is computed from the same count and
+// sum that are encoded.
+func (h *Histogram) MarshalJSON() ([]byte, error) {
+	v := histogramJSON{Count: h.Count(), Sum: h.Sum()}
+	if v.Count > 0 {
+		v.Avg = float64(v.Sum) / float64(v.Count)
+	}
+	return json.Marshal(v)
+}
+
+// Reset zeroes the sum, count, and bucket counts, starting a new observation window.
+// Bucket bounds are kept. Values are swapped individually, so an Observe racing with
+// Reset may be split across windows; readers should tolerate that small skew.
+//
+// WARNING: Do not reset a histogram that is also scraped by Prometheus. Its
+// _sum, _count, and _bucket series are counters and must never decrease; a reset looks like
+// a process restart and corrupts rate() and increase() calculations. Reset is
+// meant for internal windowed reporting only.
+//
+// Example:
+//
+//	// After each reporting interval
+//	report(reg.RequestDuration.Avg(), reg.RequestDuration.Count())
+//	reg.RequestDuration.Reset()
+func (h *Histogram) Reset() {
+	atomic.StoreUint64(&h.count, 0)
+	atomic.StoreUint64(&h.sum, 0)
+	if hb := h.buckets.Load(); hb != nil {
+		for i := range hb.counts {
+			atomic.StoreUint64(&hb.counts[i], 0)
+		}
+	}
+}
+
+// DefaultMaxLabelSeries is the default cap on labeled series held by a Registry.
+const DefaultMaxLabelSeries = 10000
+
 // Registry holds metrics for an application.
 // It provides common metrics out of the box and supports custom labeled metrics.
 type Registry struct {
 	// HTTP metrics
-	RequestsTotal   *Counter   // Total HTTP requests
-	RequestDuration *Histogram // HTTP request duration in milliseconds
+	RequestsTotal    *Counter   // Total HTTP requests
+	RequestDuration  *Histogram // HTTP request duration in milliseconds
+	RequestsInflight *Gauge     // HTTP requests currently being handled
 
 	// Rate limiting metrics
 	RateAllowed  *Counter // Requests allowed by rate limiter
@@ -78,25 +273,182 @@ type Registry struct {
 	GrpcDuration *Histogram // gRPC request duration in milliseconds
 
 	// System metrics
-	Started time.Time // When the registry was created
+	Started            time.Time // When the registry was created
+	LabelSeriesDropped *Counter  // Labeled observations dropped because the series cap was reached
 
 	// Custom labeled metrics
-	mu      sync.RWMutex
-	labeled map[string]*Counter // key: metric|labelString
+	mu            sync.RWMutex
+	labeled       map[string]*series[Counter]   // key: see buildLabelKey
+	labeledHists  map[string]*series[Histogram] // key: see buildLabelKey
+	labeledGauges map[string]*series[Gauge]     // key: see buildLabelKey
+	kinds         map[string]metricKind         // metric name -> type, to reject type conflicts
+	histBuckets   map[string][]float64          // metric name -> bucket bounds for new histogram series
+	maxSeries     int                           // Max labeled series (all types); <= 0 means unlimited
+	buildInfo     string                        // Rendered build_info label set; empty until SetBuildInfo
+}
+
+// series is a labeled metric together with the name and labels identifying it.
+// Labels are kept structurally, so rendering never has to split the lookup key.
+type series[T any] struct {
+	metric   string
+	labels   map[string]string // Copy of the caller's labels
+	rendered string            // Prometheus label set, e.g. {k="v"}; empty without labels
+	value    *T
+
+	touched atomic.Int64 // UnixNano of the last lookup or observed change (see DeleteStale)
+	mark    uint64       // Value fingerprint at the last DeleteStale; guarded by Registry.mu
+}
+
+// touch records that the series was just used.
+func (s *series[T]) touch() {
+	s.touched.Store(time.Now().UnixNano())
+}
+
+// newSeries creates a series for metric and labels, copying labels so later
+// changes by the caller don't affect it.
+func newSeries[T any](metric string, labels map[string]string) *series[T] {
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	s := &series[T]{metric: metric, labels: copied, rendered: renderLabels(copied), value: new(T)}
+	s.touch()
+	return s
+}
+
+// metricKind is the type of a labeled metric name.
+type metricKind int
+
+const (
+	kindCounter metricKind = iota + 1
+	kindHistogram
+	kindGauge
+)
+
+// String returns the metric type name used in conflict panics.
+func (k metricKind) String() string {
+	switch k {
+	case kindCounter:
+		return "counter"
+	case kindHistogram:
+		return "histogram"
+	default:
+		return "gauge"
+	}
 }
 
 // NewRegistry creates a new metrics registry with initialized counters and histograms.
 func NewRegistry() *Registry {
 	return &Registry{
-		RequestsTotal:   &Counter{},
-		RequestDuration: &Histogram{},
-		RateAllowed:     &Counter{},
-		RateRejected:    &Counter{},
-		GrpcRequests:    &Counter{},
-		GrpcDuration:    &Histogram{},
-		Started:         time.Now().UTC(),
-		labeled:         make(map[string]*Counter),
+		RequestsTotal:      &Counter{},
+		RequestDuration:    &Histogram{},
+		RequestsInflight:   &Gauge{},
+		RateAllowed:        &Counter{},
+		RateRejected:       &Counter{},
+		GrpcRequests:       &Counter{},
+		GrpcDuration:       &Histogram{},
+		Started:            time.Now().UTC(),
+		LabelSeriesDropped: &Counter{},
+		labeled:            make(map[string]*series[Counter]),
+		labeledHists:       make(map[string]*series[Histogram]),
+		labeledGauges:      make(map[string]*series[Gauge]),
+		kinds:              make(map[string]metricKind),
+		histBuckets:        make(map[string][]float64),
+		maxSeries:          DefaultMaxLabelSeries,
+	}
+}
+
+// SetMaxLabelSeries sets the cap on distinct labeled series (counters, histograms, and gauges combined).
+// Once reached, observations for new label combinations are dropped and counted in
+// LabelSeriesDropped, while existing series keep updating. This bounds memory when
+// labels carry unbounded values (random paths, many tenants) at the cost of losing
+// visibility into series created after the cap. Use max <= 0 to disable the cap.
+func (r *Registry) SetMaxLabelSeries(max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxSeries = max
+}
+
+// SetHistogramBuckets sets the bucket upper bounds for the labeled histogram metric,
+// so each of its series is rendered with _bucket series, and reports whether metric
+// uses them. Like Histogram.SetBuckets, call it before the first ObserveLabeled for
+// metric: setting the same buckets again returns true, while different buckets or a
+// series with observations leave metric unchanged and return false. It panics on
+// invalid buckets or if metric is used by another type. Reset keeps the buckets.
+//
+// Example:
+//
+//	reg.SetHistogramBuckets("http_response_bytes", metrics.DefaultSizeBucketsBytes)
+//	reg.ObserveLabeled("http_response_bytes", map[string]string{"path": "/api/users"}, int64(len(body)))
+func (r *Registry) SetHistogramBuckets(metric string, buckets []float64) bool {
+	bounds := validBuckets(buckets)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.histBuckets[metric]; ok {
+		return slices.Equal(existing, bounds)
+	}
+	r.checkKind(metric, kindHistogram)
+	for _, s := range r.labeledHists {
+		if s.metric == metric && s.value.Count() > 0 {
+			return false
+		}
+	}
+	for _, s := range r.labeledHists {
+		if s.metric == metric {
+			s.value.SetBuckets(bounds)
+		}
+	}
+	r.histBuckets[metric] = bounds
+	return true
+}
+
+// seriesFull reports whether the series cap has been reached. Caller must hold r.mu.
+func (r *Registry) seriesFull() bool {
+	return r.maxSeries > 0 && len(r.labeled)+len(r.labeledHists)+len(r.labeledGauges) >= r.maxSeries
+}
+
+// checkKind records the type of metric, panicking if the name is already used by
+// another type: a name rendered as both a counter and a gauge would produce
+// conflicting Prometheus series. Caller must hold r.mu for writing.
+func (r *Registry) checkKind(metric string, kind metricKind) {
+	if existing, ok := r.kinds[metric]; ok && existing != kind {
+		panic(fmt.Sprintf("metrics: %q is already registered as a %s, cannot use it as a %s", metric, existing, kind))
 	}
+	r.kinds[metric] = kind
+}
+
+// labeledCounter returns the counter for metric and labels, creating it if the series cap allows.
+// Returns nil when the series does not exist and the cap has been reached.
+func (r *Registry) labeledCounter(metric string, labels map[string]string) *Counter {
+	key := buildLabelKey(metric, labels)
+
+	// Fast path: read lock first
+	r.mu.RLock()
+	s, ok := r.labeled[key]
+	r.mu.RUnlock()
+
+	if ok {
+		s.touch()
+		return s.value
+	}
+
+	// Slow path: write lock to create counter
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Double-check after acquiring write lock
+	if s, ok = r.labeled[key]; ok {
+		s.touch()
+		return s.value
+	}
+	r.checkKind(metric, kindCounter)
+	if r.seriesFull() {
+		return nil
+	}
+	s = newSeries[Counter](metric, labels)
+	r.labeled[key] = s
+	return s.value
 }
 
 // IncLabeled increments a labeled counter for the given metric name and label map.
@@ -111,68 +463,335 @@ func NewRegistry() *Registry {
 //	})
 func (r *Registry) IncLabeled(metric string, labels map[string]string) {
 	// Generate stable key from sorted labels
+	c := r.labeledCounter(metric, labels)
+	if c == nil {
+		r.LabelSeriesDropped.Inc()
+		return
+	}
+
+	c.Inc()
+}
+
+// LabeledCounter returns the counter of a labeled series, creating it if needed.
+// Hold on to it in hot paths to increment the series without building its key on
+// every call. Returns nil if the series cap has been reached (the drop is counted in
+// LabelSeriesDropped). Counters obtained before Reset are no longer rendered after it.
+//
+// Example:
+//
+//	hits := reg.LabeledCounter("cache_requests_total", map[string]string{"result": "hit"})
+//	hits.Inc()
+func (r *Registry) LabeledCounter(metric string, labels map[string]string) *Counter {
+	c := r.labeledCounter(metric, labels)
+	if c == nil {
+		r.LabelSeriesDropped.Inc()
+	}
+	return c
+}
+
+// AddLabeled adds delta to a labeled counter.
+func (r *Registry) AddLabeled(metric string, labels map[string]string, delta uint64) {
+	c := r.labeledCounter(metric, labels)
+	if c == nil {
+		r.LabelSeriesDropped.Inc()
+		return
+	}
+
+	c.Add(delta)
+}
+
+// ObserveLabeled records a value in a labeled histogram.
+// Rendered as metric_sum and metric_count series sharing the same labels, plus
+// metric_bucket series if SetHistogramBuckets was called for metric.
+//
+// Example:
+//
+//	reg.ObserveLabeled("http_response_bytes", map[string]string{
+//	    "method": "GET",
+//	    "path":   "/api/users",
+//	}, int64(len(body)))
+func (r *Registry) ObserveLabeled(metric string, labels map[string]string, value int64) {
 	key := buildLabelKey(metric, labels)
 
-	// Fast path: read lock first
 	r.mu.RLock()
-	c, ok := r.labeled[key]
+	s, ok := r.labeledHists[key]
 	r.mu.RUnlock()
 
 	if !ok {
-		// Slow path: write lock to create counter
 		r.mu.Lock()
-		// Double-check after acquiring write lock
-		if c, ok = r.labeled[key]; !ok {
-			c = &Counter{}
-			r.labeled[key] = c
+		if s, ok = r.labeledHists[key]; !ok {
+			r.checkKind(metric, kindHistogram)
+			if !r.seriesFull() {
+				s = newSeries[Histogram](metric, labels)
+				if bounds, ok := r.histBuckets[metric]; ok {
+					s.value.SetBuckets(bounds)
+				}
+				r.labeledHists[key] = s
+			}
 		}
 		r.mu.Unlock()
 	}
 
-	c.Inc()
+	if s == nil {
+		r.LabelSeriesDropped.Inc()
+		return
+	}
+
+	s.touch()
+	s.value.Observe(value)
 }
 
-// AddLabeled adds delta to a labeled counter.
-func (r *Registry) AddLabeled(metric string, labels map[string]string, delta uint64) {
+// labeledGauge returns the gauge for metric and labels, creating it if the series cap allows.
+// Returns nil when the series does not exist and the cap has been reached.
+func (r *Registry) labeledGauge(metric string, labels map[string]string) *Gauge {
+	key := buildLabelKey(metric, labels)
+
+	r.mu.RLock()
+	s, ok := r.labeledGauges[key]
+	r.mu.RUnlock()
+
+	if ok {
+		s.touch()
+		return s.value
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok = r.labeledGauges[key]; ok {
+		s.touch()
+		return s.value
+	}
+	r.checkKind(metric, kindGauge)
+	if r.seriesFull() {
+		return nil
+	}
+	s = newSeries[Gauge](metric, labels)
+	r.labeledGauges[key] = s
+	return s.value
+}
+
+// SetLabeledGauge sets a labeled gauge to value.
+// A metric name can only be used for one type: using a counter or histogram
+// name as a gauge (or vice versa) panics, since it indicates a programming error.
+//
+// Example:
+//
+//	reg.SetLabeledGauge("active_sessions", map[string]string{"tenant": tenantID}, float64(n))
+func (r *Registry) SetLabeledGauge(metric string, labels map[string]string, value float64) {
+	g := r.labeledGauge(metric, labels)
+	if g == nil {
+		r.LabelSeriesDropped.Inc()
+		return
+	}
+	g.Set(value)
+}
+
+// IncLabeledGauge increments a labeled gauge by 1.
+//
+// Example:
+//
+//	reg.IncLabeledGauge("active_sessions", map[string]string{"tenant": tenantID})
+//	defer reg.DecLabeledGauge("active_sessions", map[string]string{"tenant": tenantID})
+func (r *Registry) IncLabeledGauge(metric string, labels map[string]string) {
+	g := r.labeledGauge(metric, labels)
+	if g == nil {
+		r.LabelSeriesDropped.Inc()
+		return
+	}
+	g.Inc()
+}
+
+// DecLabeledGauge decrements a labeled gauge by 1.
+func (r *Registry) DecLabeledGauge(metric string, labels map[string]string) {
+	g := r.labeledGauge(metric, labels)
+	if g == nil {
+		r.LabelSeriesDropped.Inc()
+		return
+	}
+	g.Dec()
+}
+
+// LabeledGaugeValue returns the current value of a labeled gauge series.
+// The second result is false if the series does not exist.
+func (r *Registry) LabeledGaugeValue(metric string, labels map[string]string) (float64, bool) {
 	key := buildLabelKey(metric, labels)
 
 	r.mu.RLock()
-	c, ok := r.labeled[key]
+	s, ok := r.labeledGauges[key]
 	r.mu.RUnlock()
 
 	if !ok {
-		r.mu.Lock()
-		if c, ok = r.labeled[key]; !ok {
-			c = &Counter{}
-			r.labeled[key] = c
-		}
-		r.mu.Unlock()
+		return 0, false
 	}
+	return s.value.Get(), true
+}
 
-	c.Add(delta)
+// LabeledValue returns the current value of a labeled counter series.
+// The second result is false if the series does not exist.
+// Useful for asserting on specific series in tests without parsing rendered output.
+//
+// Example:
+//
+//	v, ok := reg.LabeledValue("http_requests", map[string]string{"method": "GET", "status": "200"})
+func (r *Registry) LabeledValue(metric string, labels map[string]string) (uint64, bool) {
+	key := buildLabelKey(metric, labels)
+
+	r.mu.RLock()
+	s, ok := r.labeled[key]
+	r.mu.RUnlock()
+
+	if !ok {
+		return 0, false
+	}
+	return s.value.Get(), true
+}
+
+// LabeledHistogram returns the sum and count of a labeled histogram series.
+// The last result is false if the series does not exist.
+func (r *Registry) LabeledHistogram(metric string, labels map[string]string) (sum, count uint64, ok bool) {
+	key := buildLabelKey(metric, labels)
+
+	r.mu.RLock()
+	s, ok := r.labeledHists[key]
+	r.mu.RUnlock()
+
+	if !ok {
+		return 0, 0, false
+	}
+	return s.value.Sum(), s.value.Count(), true
 }
 
-// buildLabelKey generates a consistent key for labeled metrics.
-// Format: metric|key1=value1,key2=value2 (sorted by key)
+// ResetLabeledHistogram zeroes a single labeled histogram series, leaving the
+// series registered and every other metric untouched. Returns false if the series
+// does not exist. The same Prometheus warning as Histogram.Reset applies.
+//
+// Example:
+//
+//	reg.ResetLabeledHistogram("http_response_bytes", map[string]string{"method": "GET", "path": "/api/users"})
+func (r *Registry) ResetLabeledHistogram(metric string, labels map[string]string) bool {
+	key := buildLabelKey(metric, labels)
+
+	r.mu.RLock()
+	s, ok := r.labeledHists[key]
+	r.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+	s.value.Reset()
+	return true
+}
+
+// DeleteStale removes labeled series (counters, histograms, and gauges) that have
+// not been updated within maxAge and returns how many were removed. A series counts
+// as updated when it is looked up by IncLabeled, ObserveLabeled, a gauge setter, and
+// the like, or when its value changed since the previous DeleteStale call, so counters
+// held from LabeledCounter stay alive while they keep counting. Use it to bound series
+// for ephemeral labels such as connection IDs, alongside SetMaxLabelSeries.
+//
+// A removed series disappears from the output. If its labels come back, it starts
+// from zero: for counters and histograms that looks like a counter reset to
+// Prometheus, which rate() and increase() handle, so pick maxAge well above the
+// scrape interval. Counters held from LabeledCounter before their series was removed
+// are no longer rendered.
+//
+// Example:
+//
+//	go func() {
+//	    for range time.Tick(time.Minute) {
+//	        reg.DeleteStale(15 * time.Minute)
+//	    }
+//	}()
+func (r *Registry) DeleteStale(maxAge time.Duration) int {
+	now := time.Now()
+	cutoff := now.Add(-maxAge).UnixNano()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return deleteStale(r.labeled, now, cutoff, (*Counter).Get) +
+		deleteStale(r.labeledHists, now, cutoff, (*Histogram).Count) +
+		deleteStale(r.labeledGauges, now, cutoff, func(g *Gauge) uint64 { return atomic.LoadUint64(&g.bits) })
+}
+
+// deleteStale removes series from m last touched before cutoff, first marking series
+// whose fingerprint changed since the last sweep as touched at now. Caller must hold r.mu for writing.
+func deleteStale[T any](m map[string]*series[T], now time.Time, cutoff int64, fingerprint func(*T) uint64) int {
+	removed := 0
+	for key, s := range m {
+		if fp := fingerprint(s.value); fp != s.mark {
+			s.mark = fp
+			s.touched.Store(now.UnixNano())
+		}
+		if s.touched.Load() < cutoff {
+			delete(m, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// buildLabelKey generates a consistent lookup key for labeled metrics.
+// Format: metric|key1="value1",key2="value2" (sorted by key, values escaped)
+// Values are escaped so values containing ',', '=', or quotes can't collide with
+// other label sets. The key is only used for lookups; rendering uses the labels
+// stored in the series.
 func buildLabelKey(metric string, labels map[string]string) string {
 	if len(labels) == 0 {
 		return metric
 	}
+	return metric + "|" + labelPairs(labels)
+}
+
+// renderLabels formats labels as a Prometheus label set: {label1="value1",label2="value2"},
+// sorted by name with values escaped. Returns "" if there are no labels.
+func renderLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + labelPairs(labels) + "}"
+}
 
-	// Sort keys for consistency
+// labelPairs joins labels as name="value" pairs sorted by name, with values escaped.
+func labelPairs(labels map[string]string) string {
 	keys := make([]string, 0, len(labels))
 	for k := range labels {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
-	// Build label string
 	parts := make([]string, 0, len(keys))
 	for _, k := range keys {
-		parts = append(parts, k+"="+labels[k])
+		parts = append(parts, k+"=\""+escapeLabelValue(labels[k])+"\"")
 	}
+	return strings.Join(parts, ",")
+}
 
-	return metric + "|" + strings.Join(parts, ",")
+// SetBuildInfo records the running build, rendered as a constant build_info gauge
+// with version, commit, date, and go_version (runtime.Version()) labels. Dashboards
+// can join on it to correlate metric changes with deploys. Calling it again replaces
+// the labels; build_info is not rendered until it has been called, and Reset keeps it.
+//
+// Example:
+//
+//	reg.SetBuildInfo(version, commit, date) // e.g. set via -ldflags "-X main.version=..."
+//	// build_info{commit="abc1234",date="2025-10-11",go_version="go1.24.0",version="1.2.0"} 1
+func (r *Registry) SetBuildInfo(version, commit, date string) {
+	lbls := renderLabels(map[string]string{
+		"version":    version,
+		"commit":     commit,
+		"date":       date,
+		"go_version": runtime.Version(),
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buildInfo = lbls
+}
+
+// Uptime returns how long ago the registry was created, as rendered in uptime_seconds.
+func (r *Registry) Uptime() time.Duration {
+	return time.Since(r.Started)
 }
 
 // RenderPrometheus outputs metrics in Prometheus text format.
@@ -183,9 +802,10 @@ func buildLabelKey(metric string, labels map[string]string) string {
 //	http_requests_total 12345
 //	http_request_duration_ms_avg 45.67
 //	uptime_seconds 3600
+//	build_info{commit="abc1234",date="2025-10-11",go_version="go1.24.0",version="1.2.0"} 1
 //	custom_metric{label1="value1",label2="value2"} 42
 func (r *Registry) RenderPrometheus() string {
-	uptime := time.Since(r.Started).Seconds()
+	uptime := r.Uptime().Seconds()
 
 	sb := &strings.Builder{}
 
@@ -194,47 +814,108 @@ func (r *Registry) RenderPrometheus() string {
 	fmt.Fprintf(sb, "http_request_duration_ms_avg %.2f\n", r.RequestDuration.Avg())
 	fmt.Fprintf(sb, "http_request_duration_ms_sum %d\n", r.RequestDuration.Sum())
 	fmt.Fprintf(sb, "http_request_duration_ms_count %d\n", r.RequestDuration.Count())
+	writeBuckets(sb, "http_request_duration_ms", "", r.RequestDuration)
+	fmt.Fprintf(sb, "http_inflight_requests %s\n", strconv.FormatFloat(r.RequestsInflight.Get(), 'g', -1, 64))
 	fmt.Fprintf(sb, "rate_allowed_total %d\n", r.RateAllowed.Get())
 	fmt.Fprintf(sb, "rate_rejected_total %d\n", r.RateRejected.Get())
 	fmt.Fprintf(sb, "uptime_seconds %.0f\n", uptime)
 	fmt.Fprintf(sb, "grpc_requests_total %d\n", r.GrpcRequests.Get())
 	fmt.Fprintf(sb, "grpc_request_duration_ms_avg %.2f\n", r.GrpcDuration.Avg())
+	fmt.Fprintf(sb, "metrics_label_series_dropped_total %d\n", r.LabelSeriesDropped.Get())
 
 	// Labeled metrics
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for key, counter := range r.labeled {
-		// Parse key: metric|label1=value1,label2=value2
-		parts := strings.SplitN(key, "|", 2)
-		metric := parts[0]
-		lbls := ""
-
-		if len(parts) == 2 && parts[1] != "" {
-			// Convert label string to Prometheus format: {label1="value1",label2="value2"}
-			lblPairs := strings.Split(parts[1], ",")
-			for i, p := range lblPairs {
-				lblPairs[i] = strings.ReplaceAll(p, "=", "=\"") + "\""
-			}
-			lbls = "{" + strings.Join(lblPairs, ",") + "}"
-		}
+	if r.buildInfo != "" {
+		fmt.Fprintf(sb, "build_info%s 1\n", r.buildInfo)
+	}
+
+	for _, s := range r.labeled {
+		fmt.Fprintf(sb, "%s%s %d\n", s.metric, s.rendered, s.value.Get())
+	}
+
+	for _, s := range r.labeledHists {
+		fmt.Fprintf(sb, "%s_sum%s %d\n", s.metric, s.rendered, s.value.Sum())
+		fmt.Fprintf(sb, "%s_count%s %d\n", s.metric, s.rendered, s.value.Count())
+		writeBuckets(sb, s.metric, s.rendered, s.value)
+	}
 
-		fmt.Fprintf(sb, "%s%s %d\n", metric, lbls, counter.Get())
+	for _, s := range r.labeledGauges {
+		fmt.Fprintf(sb, "%s%s %s\n", s.metric, s.rendered, strconv.FormatFloat(s.value.Get(), 'g', -1, 64))
 	}
 
 	return sb.String()
 }
 
-// Reset resets all metrics to zero. Useful for testing.
+// writeBuckets writes the metric_bucket series of h, with rendered as the other
+// labels. Writes nothing if h has no buckets.
+func writeBuckets(sb *strings.Builder, metric, rendered string, h *Histogram) {
+	for _, sample := range bucketSamples(metric, rendered, h) {
+		sb.WriteString(sample)
+		sb.WriteByte('\n')
+	}
+}
+
+// bucketSamples returns the metric_bucket samples of h, from the lowest bound to
+// le="+Inf", or nil if h has no buckets.
+func bucketSamples(metric, rendered string, h *Histogram) []string {
+	bounds, cumulative := h.Buckets()
+	if bounds == nil {
+		return nil
+	}
+	samples := make([]string, 0, len(bounds))
+	for i, b := range bounds {
+		samples = append(samples, fmt.Sprintf("%s_bucket%s %d", metric, bucketLabels(rendered, formatBound(b)), cumulative[i]))
+	}
+	return samples
+}
+
+// bucketLabels adds an le label to a rendered label set.
+func bucketLabels(rendered, le string) string {
+	if rendered == "" {
+		return `{le="` + le + `"}`
+	}
+	return rendered[:len(rendered)-1] + `,le="` + le + `"}`
+}
+
+// formatBound formats a bucket upper bound for the le label, e.g. 0.5 or +Inf.
+func formatBound(b float64) string {
+	return strconv.FormatFloat(b, 'g', -1, 64)
+}
+
+// labelValueEscaper escapes label values per the Prometheus text format.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes backslashes, double quotes, and newlines in a label value,
+// so arbitrary values (e.g. tenant names) can't corrupt the exposition output.
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+// Reset resets all metrics to zero. Useful for testing. Histogram buckets are kept.
 func (r *Registry) Reset() {
 	r.RequestsTotal = &Counter{}
-	r.RequestDuration = &Histogram{}
+	r.RequestDuration = resetHistogram(r.RequestDuration)
+	r.RequestsInflight = &Gauge{}
 	r.RateAllowed = &Counter{}
 	r.RateRejected = &Counter{}
 	r.GrpcRequests = &Counter{}
-	r.GrpcDuration = &Histogram{}
+	r.GrpcDuration = resetHistogram(r.GrpcDuration)
+	r.LabelSeriesDropped = &Counter{}
 
 	r.mu.Lock()
-	r.labeled = make(map[string]*Counter)
+	r.labeled = make(map[string]*series[Counter])
+	r.labeledHists = make(map[string]*series[Histogram])
+	r.labeledGauges = make(map[string]*series[Gauge])
+	r.kinds = make(map[string]metricKind)
 	r.mu.Unlock()
 }
+
+// resetHistogram returns an empty Histogram with the same buckets as h.
+func resetHistogram(h *Histogram) *Histogram {
+	if bounds := h.bounds(); bounds != nil {
+		return NewHistogram(bounds)
+	}
+	return &Histogram{}
+}
@@ -1,10 +1,15 @@
 package metrics
 
 import (
+	"encoding/json"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCounter_Inc(t *testing.T) {
@@ -107,6 +112,20 @@ func TestHistogram_ConcurrentObserve(t *testing.T) {
 	assert.Equal(t, 10.0, avg)
 }
 
+func TestHistogram_Reset(t *testing.T) {
+	h := &Histogram{}
+	h.Observe(10)
+	h.Observe(20)
+
+	h.Reset()
+	assert.Equal(t, uint64(0), h.Sum())
+	assert.Equal(t, uint64(0), h.Count())
+	assert.Equal(t, 0.0, h.Avg())
+
+	h.Observe(5)
+	assert.Equal(t, 5.0, h.Avg())
+}
+
 func TestRegistry_IncLabeled(t *testing.T) {
 	r := NewRegistry()
 
@@ -115,11 +134,15 @@ func TestRegistry_IncLabeled(t *testing.T) {
 	r.IncLabeled("test_metric", map[string]string{"status": "404", "method": "GET"})
 	r.IncLabeled("test_metric", map[string]string{"status": "200", "method": "POST"})
 
-	// Verify via Prometheus output
-	output := r.RenderPrometheus()
-	assert.Contains(t, output, `test_metric{method="GET",status="200"} 2`)
-	assert.Contains(t, output, `test_metric{method="GET",status="404"} 1`)
-	assert.Contains(t, output, `test_metric{method="POST",status="200"} 1`)
+	v, ok := r.LabeledValue("test_metric", map[string]string{"method": "GET", "status": "200"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), v)
+
+	v, _ = r.LabeledValue("test_metric", map[string]string{"method": "GET", "status": "404"})
+	assert.Equal(t, uint64(1), v)
+
+	v, _ = r.LabeledValue("test_metric", map[string]string{"method": "POST", "status": "200"})
+	assert.Equal(t, uint64(1), v)
 }
 
 func TestRegistry_AddLabeled(t *testing.T) {
@@ -129,9 +152,11 @@ func TestRegistry_AddLabeled(t *testing.T) {
 	r.AddLabeled("test_metric", map[string]string{"type": "user"}, 10)
 	r.AddLabeled("test_metric", map[string]string{"type": "admin"}, 3)
 
-	output := r.RenderPrometheus()
-	assert.Contains(t, output, `test_metric{type="user"} 15`)
-	assert.Contains(t, output, `test_metric{type="admin"} 3`)
+	v, _ := r.LabeledValue("test_metric", map[string]string{"type": "user"})
+	assert.Equal(t, uint64(15), v)
+
+	v, _ = r.LabeledValue("test_metric", map[string]string{"type": "admin"})
+	assert.Equal(t, uint64(3), v)
 }
 
 func TestRenderPrometheus(t *testing.T) {
@@ -184,6 +209,60 @@ func TestRenderPrometheus_Sorting(t *testing.T) {
 	assert.Contains(t, output, `test_metric{a="1",b="2"} 1`)
 }
 
+func TestRenderPrometheus_EscapesLabelValues(t *testing.T) {
+	r := NewRegistry()
+
+	r.IncLabeled("tenant_requests", map[string]string{"tenant": `Acme "Corp"`})
+	r.IncLabeled("tenant_requests", map[string]string{"tenant": "line1\nline2"})
+	r.IncLabeled("tenant_requests", map[string]string{"tenant": `C:\data`})
+
+	output := r.RenderPrometheus()
+
+	assert.Contains(t, output, `tenant_requests{tenant="Acme \"Corp\""} 1`)
+	assert.Contains(t, output, `tenant_requests{tenant="line1\nline2"} 1`)
+	assert.Contains(t, output, `tenant_requests{tenant="C:\\data"} 1`)
+
+	// Every sample must stay on its own line
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		assert.Regexp(t, `^[a-z_]+(\{.*\})? [0-9.e+-]+$`, line)
+	}
+}
+
+func TestRegistry_LabelValuesWithSeparatorsDontCollide(t *testing.T) {
+	r := NewRegistry()
+
+	r.IncLabeled("m", map[string]string{"a": "x,b=y"})
+	r.IncLabeled("m", map[string]string{"a": "x", "b": "y"})
+
+	v, ok := r.LabeledValue("m", map[string]string{"a": "x,b=y"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), v)
+	assert.Contains(t, r.RenderPrometheus(), `m{a="x,b=y"} 1`)
+}
+
+func TestRegistry_SeriesKeepLabelsStructurally(t *testing.T) {
+	r := NewRegistry()
+
+	labels := map[string]string{"tenant": `a,b="c"`, "plan": "pro=1"}
+	r.IncLabeled("tenant_requests", labels)
+	r.ObserveLabeled("tenant_latency", labels, 5)
+	r.SetLabeledGauge("tenant_sessions", labels, 2)
+	labels["tenant"] = "mutated" // Must not affect the stored series
+
+	output := r.RenderPrometheus()
+	assert.Contains(t, output, `tenant_requests{plan="pro=1",tenant="a,b=\"c\""} 1`)
+	assert.Contains(t, output, `tenant_latency_sum{plan="pro=1",tenant="a,b=\"c\""} 5`)
+	assert.Contains(t, output, `tenant_sessions{plan="pro=1",tenant="a,b=\"c\""} 2`)
+	assert.NotContains(t, output, "mutated")
+
+	om := r.RenderOpenMetrics()
+	assert.Contains(t, om, `tenant_requests{plan="pro=1",tenant="a,b=\"c\""} 1`)
+
+	// The metric name is not split at the key separator either
+	r.IncLabeled("odd|name", nil)
+	assert.Contains(t, r.RenderPrometheus(), "odd|name 1\n")
+}
+
 func TestRenderPrometheus_EmptyLabels(t *testing.T) {
 	r := NewRegistry()
 
@@ -208,3 +287,282 @@ func TestRegistry_Reset(t *testing.T) {
 	output := r.RenderPrometheus()
 	assert.NotContains(t, output, "test_metric")
 }
+
+func TestRegistry_ObserveLabeled(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveLabeled("payload_bytes", map[string]string{"method": "POST"}, 100)
+	r.ObserveLabeled("payload_bytes", map[string]string{"method": "POST"}, 50)
+
+	output := r.RenderPrometheus()
+	assert.Contains(t, output, `payload_bytes_sum{method="POST"} 150`)
+	assert.Contains(t, output, `payload_bytes_count{method="POST"} 2`)
+
+	r.Reset()
+	assert.NotContains(t, r.RenderPrometheus(), "payload_bytes")
+}
+
+func TestRegistry_MaxLabelSeries(t *testing.T) {
+	r := NewRegistry()
+	r.SetMaxLabelSeries(2)
+
+	r.IncLabeled("test_metric", map[string]string{"path": "/a"})
+	r.IncLabeled("test_metric", map[string]string{"path": "/b"})
+	r.IncLabeled("test_metric", map[string]string{"path": "/c"})      // dropped
+	r.IncLabeled("test_metric", map[string]string{"path": "/a"})      // existing series still updates
+	r.ObserveLabeled("test_hist", map[string]string{"path": "/a"}, 1) // dropped
+
+	output := r.RenderPrometheus()
+	assert.Contains(t, output, `test_metric{path="/a"} 2`)
+	assert.NotContains(t, output, `path="/c"`)
+	assert.NotContains(t, output, "test_hist")
+	assert.Equal(t, uint64(2), r.LabelSeriesDropped.Get())
+	assert.Contains(t, output, "metrics_label_series_dropped_total 2")
+}
+
+func TestRegistry_LabeledCounter(t *testing.T) {
+	r := NewRegistry()
+	r.SetMaxLabelSeries(1)
+
+	hits := r.LabeledCounter("cache_requests_total", map[string]string{"result": "hit"})
+	hits.Inc()
+	hits.Inc()
+	r.IncLabeled("cache_requests_total", map[string]string{"result": "hit"})
+
+	v, _ := r.LabeledValue("cache_requests_total", map[string]string{"result": "hit"})
+	assert.Equal(t, uint64(3), v)
+
+	assert.Nil(t, r.LabeledCounter("cache_requests_total", map[string]string{"result": "miss"}))
+	assert.Equal(t, uint64(1), r.LabelSeriesDropped.Get())
+}
+
+func TestRegistry_MaxLabelSeriesDisabled(t *testing.T) {
+	r := NewRegistry()
+	r.SetMaxLabelSeries(0)
+
+	for i := 0; i < DefaultMaxLabelSeries+10; i++ {
+		r.AddLabeled("test_metric", map[string]string{"i": strconv.Itoa(i)}, 1)
+	}
+	assert.Equal(t, uint64(0), r.LabelSeriesDropped.Get())
+}
+
+func TestRegistry_LabeledValueMissing(t *testing.T) {
+	r := NewRegistry()
+
+	_, ok := r.LabeledValue("missing", map[string]string{"a": "b"})
+	assert.False(t, ok)
+
+	_, _, ok = r.LabeledHistogram("missing", nil)
+	assert.False(t, ok)
+}
+
+func TestRegistry_LabeledHistogram(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveLabeled("size", map[string]string{"path": "/a"}, 10)
+	r.ObserveLabeled("size", map[string]string{"path": "/a"}, 30)
+
+	sum, count, ok := r.LabeledHistogram("size", map[string]string{"path": "/a"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(40), sum)
+	assert.Equal(t, uint64(2), count)
+}
+
+func TestRegistry_ResetLabeledHistogram(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveLabeled("size", map[string]string{"path": "/a"}, 10)
+	r.ObserveLabeled("size", map[string]string{"path": "/b"}, 20)
+	r.IncLabeled("hits", map[string]string{"path": "/a"})
+
+	assert.True(t, r.ResetLabeledHistogram("size", map[string]string{"path": "/a"}))
+	assert.False(t, r.ResetLabeledHistogram("size", map[string]string{"path": "/c"}))
+
+	sum, count, ok := r.LabeledHistogram("size", map[string]string{"path": "/a"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), sum)
+	assert.Equal(t, uint64(0), count)
+
+	sum, _, _ = r.LabeledHistogram("size", map[string]string{"path": "/b"})
+	assert.Equal(t, uint64(20), sum)
+
+	hits, _ := r.LabeledValue("hits", map[string]string{"path": "/a"})
+	assert.Equal(t, uint64(1), hits)
+}
+
+func TestGauge(t *testing.T) {
+	g := &Gauge{}
+
+	g.Set(2.5)
+	g.Inc()
+	g.Dec()
+	g.Add(-1)
+	assert.Equal(t, 1.5, g.Get())
+}
+
+func TestRegistry_LabeledGauge(t *testing.T) {
+	r := NewRegistry()
+	labels := map[string]string{"tenant": "t1"}
+
+	r.IncLabeledGauge("active_sessions", labels)
+	r.IncLabeledGauge("active_sessions", labels)
+	r.DecLabeledGauge("active_sessions", labels)
+	v, ok := r.LabeledGaugeValue("active_sessions", labels)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, v)
+
+	r.SetLabeledGauge("queue_depth", nil, 0.25)
+	out := r.RenderPrometheus()
+	assert.Contains(t, out, `active_sessions{tenant="t1"} 1`)
+	assert.Contains(t, out, "queue_depth 0.25")
+}
+
+func TestRegistry_LabeledTypeConflictPanics(t *testing.T) {
+	r := NewRegistry()
+	r.IncLabeled("sessions", map[string]string{"tenant": "t1"})
+
+	assert.Panics(t, func() {
+		r.SetLabeledGauge("sessions", map[string]string{"tenant": "t2"}, 1)
+	})
+	assert.Panics(t, func() {
+		r.ObserveLabeled("sessions", nil, 1)
+	})
+}
+
+func TestRenderOpenMetrics(t *testing.T) {
+	reg := NewRegistry()
+	reg.RequestsTotal.Add(3)
+	reg.IncLabeled("http_requests", map[string]string{"method": "GET", "status": "200"})
+	reg.IncLabeled("jobs_processed_total", map[string]string{"queue": "email"})
+	reg.ObserveLabeled("http_response_bytes", map[string]string{"path": "/a"}, 100)
+	reg.SetLabeledGauge("active_sessions", map[string]string{"tenant": "t1"}, 2)
+
+	output := reg.RenderOpenMetrics()
+
+	assert.True(t, strings.HasSuffix(output, "# EOF\n"))
+	assert.Contains(t, output, "# TYPE http_requests unknown\nhttp_requests{method=\"GET\",status=\"200\"} 1\n")
+	assert.Contains(t, output, "# TYPE http_requests_total unknown\nhttp_requests_total 3\n")
+	assert.Contains(t, output, "# TYPE jobs_processed counter\njobs_processed_total{queue=\"email\"} 1\n")
+	assert.Contains(t, output, "# TYPE rate_allowed counter\nrate_allowed_total 0\n")
+	assert.Contains(t, output, "# TYPE http_response_bytes summary\nhttp_response_bytes_sum{path=\"/a\"} 100\nhttp_response_bytes_count{path=\"/a\"} 1\n")
+	assert.Contains(t, output, "# TYPE active_sessions gauge\nactive_sessions{tenant=\"t1\"} 2\n")
+
+	// Every family is declared exactly once
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		if name, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			name = strings.Fields(name)[0]
+			assert.False(t, seen[name], "family %s declared twice", name)
+			seen[name] = true
+		}
+	}
+}
+
+func TestSetBuildInfo(t *testing.T) {
+	reg := NewRegistry()
+	assert.NotContains(t, reg.RenderPrometheus(), "build_info")
+
+	reg.SetBuildInfo("1.2.0", "abc1234", "2025-10-11")
+	want := `build_info{commit="abc1234",date="2025-10-11",go_version="` + runtime.Version() + `",version="1.2.0"} 1`
+	assert.Contains(t, reg.RenderPrometheus(), want+"\n")
+	assert.Contains(t, reg.RenderOpenMetrics(), "# TYPE build_info gauge\n"+want+"\n")
+
+	reg.SetBuildInfo("1.3.0", "def5678", "2025-10-12")
+	output := reg.RenderPrometheus()
+	assert.Equal(t, 1, strings.Count(output, "build_info{"))
+	assert.Contains(t, output, `version="1.3.0"`)
+
+	reg.Reset()
+	assert.Contains(t, reg.RenderPrometheus(), `version="1.3.0"`)
+}
+
+func TestUptime(t *testing.T) {
+	reg := NewRegistry()
+	reg.Started = time.Now().Add(-90 * time.Second)
+
+	assert.GreaterOrEqual(t, reg.Uptime(), 90*time.Second)
+	assert.Contains(t, reg.RenderPrometheus(), "uptime_seconds 90\n")
+}
+
+func TestRender_NegotiatesFormat(t *testing.T) {
+	reg := NewRegistry()
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", ContentTypePrometheus},
+		{"*/*", ContentTypePrometheus},
+		{"text/plain;version=0.0.4", ContentTypePrometheus},
+		{"application/openmetrics-text;version=1.0.0", ContentTypeOpenMetrics},
+		{"application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1", ContentTypeOpenMetrics},
+		{"application/openmetrics-text;q=0.2,text/plain;q=0.9", ContentTypePrometheus},
+	}
+
+	for _, tt := range tests {
+		body, contentType := reg.Render(tt.accept)
+		assert.Equal(t, tt.contentType, contentType, "accept %q", tt.accept)
+		assert.Equal(t, tt.contentType == ContentTypeOpenMetrics, strings.HasSuffix(body, "# EOF\n"), "accept %q", tt.accept)
+	}
+}
+
+func TestRegistry_DeleteStale(t *testing.T) {
+	r := NewRegistry()
+	r.IncLabeled("conn_requests", map[string]string{"conn": "1"})
+	r.IncLabeled("conn_requests", map[string]string{"conn": "2"})
+	r.ObserveLabeled("conn_bytes", map[string]string{"conn": "1"}, 10)
+	r.SetLabeledGauge("conn_open", map[string]string{"conn": "1"}, 1)
+	held := r.LabeledCounter("conn_errors", map[string]string{"conn": "3"})
+
+	// Nothing is stale right after being updated
+	assert.Equal(t, 0, r.DeleteStale(time.Minute))
+
+	// Age every series as if the last update was an hour ago
+	past := time.Now().Add(-time.Hour).UnixNano()
+	r.mu.Lock()
+	for _, s := range r.labeled {
+		s.touched.Store(past)
+	}
+	for _, s := range r.labeledHists {
+		s.touched.Store(past)
+	}
+	for _, s := range r.labeledGauges {
+		s.touched.Store(past)
+	}
+	r.mu.Unlock()
+
+	r.IncLabeled("conn_requests", map[string]string{"conn": "2"}) // Looked up again
+	held.Inc()                                                    // Changed through a held counter
+
+	assert.Equal(t, 3, r.DeleteStale(time.Minute))
+
+	_, ok := r.LabeledValue("conn_requests", map[string]string{"conn": "1"})
+	assert.False(t, ok)
+	v, ok := r.LabeledValue("conn_requests", map[string]string{"conn": "2"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), v)
+	v, ok = r.LabeledValue("conn_errors", map[string]string{"conn": "3"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), v)
+	assert.NotContains(t, r.RenderPrometheus(), `conn="1"`)
+
+	// A removed counter starts from zero when its labels come back
+	r.IncLabeled("conn_requests", map[string]string{"conn": "1"})
+	v, _ = r.LabeledValue("conn_requests", map[string]string{"conn": "1"})
+	assert.Equal(t, uint64(1), v)
+}
+
+func TestCounterAndHistogramMarshalJSON(t *testing.T) {
+	r := NewRegistry()
+	r.RequestsTotal.Add(3)
+	r.RequestDuration.Observe(10)
+	r.RequestDuration.Observe(20)
+
+	data, err := json.Marshal(struct {
+		Requests *Counter   `json:"requests"`
+		Latency  *Histogram `json:"latency"`
+		Empty    *Histogram `json:"empty"`
+	}{r.RequestsTotal, r.RequestDuration, &Histogram{}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"requests":3,"latency":{"avg":15,"count":2,"sum":30},"empty":{"avg":0,"count":0,"sum":0}}`, string(data))
+}
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Content types for the exposition formats, including the format version.
+const (
+	ContentTypePrometheus  = "text/plain; version=0.0.4; charset=utf-8"
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// omFamily is a metric family in the OpenMetrics output: a # TYPE line followed
+// by its samples, which must be contiguous.
+type omFamily struct {
+	typ     string
+	samples []string
+}
+
+// omWriter groups samples into families so each family is rendered once.
+type omWriter struct {
+	families map[string]*omFamily
+}
+
+// add appends sample to family name, creating it with typ. It returns false, adding
+// nothing, if the family already exists with a different type.
+func (w *omWriter) add(name, typ, sample string) bool {
+	f, ok := w.families[name]
+	if !ok {
+		f = &omFamily{typ: typ}
+		w.families[name] = f
+	} else if f.typ != typ {
+		return false
+	}
+	f.samples = append(f.samples, sample)
+	return true
+}
+
+// addCounter adds a counter sample. Names ending in _total form a counter family
+// without the suffix, as OpenMetrics requires; other names, or a family name already
+// taken by another type (e.g. http_requests_total next to labeled http_requests),
+// are exposed as unknown so sample names match RenderPrometheus.
+func (w *omWriter) addCounter(metric, lbls string, value uint64) {
+	sample := fmt.Sprintf("%s%s %d", metric, lbls, value)
+	if name, ok := strings.CutSuffix(metric, "_total"); ok && w.add(name, "counter", sample) {
+		return
+	}
+
+	f, ok := w.families[metric]
+	if !ok {
+		f = &omFamily{typ: "unknown"}
+		w.families[metric] = f
+	}
+	f.samples = append(f.samples, sample)
+}
+
+// addHistogram adds the samples of h to family metric, typed as a histogram if h
+// has buckets and as a summary (_sum and _count only) otherwise.
+func (w *omWriter) addHistogram(metric, lbls string, h *Histogram) {
+	typ, samples := "summary", bucketSamples(metric, lbls, h)
+	if samples != nil {
+		typ = "histogram"
+	}
+	for _, sample := range samples {
+		w.add(metric, typ, sample)
+	}
+	w.add(metric, typ, fmt.Sprintf("%s_sum%s %d", metric, lbls, h.Sum()))
+	w.add(metric, typ, fmt.Sprintf("%s_count%s %d", metric, lbls, h.Count()))
+}
+
+// RenderOpenMetrics outputs the same series as RenderPrometheus in the OpenMetrics
+// text format: samples are grouped under # TYPE lines and the output ends with # EOF.
+// Sample names and values are identical to RenderPrometheus, so dashboards work with
+// either format. Histograms with buckets are typed as histograms, others as summaries
+// (_sum and _count only); counters whose name doesn't end in _total are typed as unknown.
+//
+// Use Render or middleware.MetricsHandler to pick the format from the Accept header.
+//
+// Example output:
+//
+//	# TYPE http_request_duration_ms summary
+//	http_request_duration_ms_sum 4567
+//	http_request_duration_ms_count 100
+//	# TYPE http_requests unknown
+//	http_requests{method="GET",path="/api/users",status="200"} 42
+//	# EOF
+func (r *Registry) RenderOpenMetrics() string {
+	w := &omWriter{families: make(map[string]*omFamily)}
+
+	// Gauges and histograms first, so counters can detect family name clashes
+	w.addHistogram("http_request_duration_ms", "", r.RequestDuration)
+	w.add("http_request_duration_ms_avg", "gauge", fmt.Sprintf("http_request_duration_ms_avg %.2f", r.RequestDuration.Avg()))
+	w.add("http_inflight_requests", "gauge", "http_inflight_requests "+strconv.FormatFloat(r.RequestsInflight.Get(), 'g', -1, 64))
+	w.add("uptime_seconds", "gauge", fmt.Sprintf("uptime_seconds %.0f", r.Uptime().Seconds()))
+	w.add("grpc_request_duration_ms_avg", "gauge", fmt.Sprintf("grpc_request_duration_ms_avg %.2f", r.GrpcDuration.Avg()))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.buildInfo != "" {
+		w.add("build_info", "gauge", "build_info"+r.buildInfo+" 1")
+	}
+
+	for _, key := range sortedKeys(r.labeledHists) {
+		s := r.labeledHists[key]
+		w.addHistogram(s.metric, s.rendered, s.value)
+	}
+
+	for _, key := range sortedKeys(r.labeledGauges) {
+		s := r.labeledGauges[key]
+		w.add(s.metric, "gauge", s.metric+s.rendered+" "+strconv.FormatFloat(s.value.Get(), 'g', -1, 64))
+	}
+
+	// Labeled counters before the base counters, so http_requests{...} keeps its
+	// family name and http_requests_total falls back to unknown
+	for _, key := range sortedKeys(r.labeled) {
+		s := r.labeled[key]
+		w.addCounter(s.metric, s.rendered, s.value.Get())
+	}
+
+	w.addCounter("http_requests_total", "", r.RequestsTotal.Get())
+	w.addCounter("rate_allowed_total", "", r.RateAllowed.Get())
+	w.addCounter("rate_rejected_total", "", r.RateRejected.Get())
+	w.addCounter("grpc_requests_total", "", r.GrpcRequests.Get())
+	w.addCounter("metrics_label_series_dropped_total", "", r.LabelSeriesDropped.Get())
+
+	names := make([]string, 0, len(w.families))
+	for name := range w.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sb := &strings.Builder{}
+	for _, name := range names {
+		f := w.families[name]
+		fmt.Fprintf(sb, "# TYPE %s %s\n", name, f.typ)
+		for _, sample := range f.samples {
+			sb.WriteString(sample)
+			sb.WriteByte('\n')
+		}
+	}
+	sb.WriteString("# EOF\n")
+
+	return sb.String()
+}
+
+// Render negotiates the exposition format from an HTTP Accept header value and
+// returns the rendered metrics with the matching Content-Type. OpenMetrics is served
+// when application/openmetrics-text is accepted with at least the quality of
+// text/plain; otherwise, including for an empty header, the Prometheus text format is used.
+//
+// Example:
+//
+//	http.HandleFunc("/metrics", func(w http.ResponseWriter, req *http.Request) {
+//	    body, contentType := reg.Render(req.Header.Get("Accept"))
+//	    w.Header().Set("Content-Type", contentType)
+//	    io.WriteString(w, body)
+//	})
+func (r *Registry) Render(accept string) (body, contentType string) {
+	if acceptsOpenMetrics(accept) {
+		return r.RenderOpenMetrics(), ContentTypeOpenMetrics
+	}
+	return r.RenderPrometheus(), ContentTypePrometheus
+}
+
+// acceptsOpenMetrics reports whether the Accept header prefers OpenMetrics over
+// the Prometheus text format. Wildcards count towards text/plain.
+func acceptsOpenMetrics(accept string) bool {
+	var openMetricsQ, textQ float64
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+		q := 1.0
+		for _, p := range params[1:] {
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.EqualFold(k, "q") {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		switch mediaType {
+		case "application/openmetrics-text":
+			openMetricsQ = max(openMetricsQ, q)
+		case "text/plain", "text/*", "*/*":
+			textQ = max(textQ, q)
+		}
+	}
+	return openMetricsQ > 0 && openMetricsQ >= textQ
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
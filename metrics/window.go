@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultWindowSize is the default number of samples kept by a SlidingWindowHistogram.
+const DefaultWindowSize = 1024
+
+// SlidingWindowHistogram keeps the most recent observations in a fixed-size ring
+// buffer and computes quantiles over them. It is independent of Histogram and the
+// Registry, so cumulative Prometheus series are unaffected.
+//
+// Approximation: quantiles describe only the last Size observations, not a time
+// window, so under low traffic the "recent" window may span a long period. Quantiles
+// use the nearest-rank method on the retained samples; with n samples, the reported
+// q-quantile is exact for those samples, and its rank error is at most 1/n (e.g.
+// p99 over 1024 samples is drawn from roughly the 10 slowest). Tail quantiles need
+// a window large enough to contain several tail samples.
+//
+// Memory is bounded at 8 bytes per sample; Quantile copies and sorts the window
+// (O(n log n)), so it suits status pages and periodic reporting, not hot paths.
+type SlidingWindowHistogram struct {
+	mu      sync.Mutex
+	samples []int64
+	next    int  // Index of the slot to overwrite next
+	full    bool // Whether the buffer has wrapped
+}
+
+// NewSlidingWindowHistogram creates a histogram retaining the last size observations.
+// A size <= 0 uses DefaultWindowSize.
+//
+// Example:
+//
+//	window := metrics.NewSlidingWindowHistogram(2048)
+//	window.Observe(time.Since(start).Milliseconds())
+//	p99 := window.Quantile(0.99)
+func NewSlidingWindowHistogram(size int) *SlidingWindowHistogram {
+	if size <= 0 {
+		size = DefaultWindowSize
+	}
+	return &SlidingWindowHistogram{samples: make([]int64, size)}
+}
+
+// Observe records a value, evicting the oldest one once the window is full.
+func (h *SlidingWindowHistogram) Observe(value int64) {
+	h.mu.Lock()
+	h.samples[h.next] = value
+	h.next++
+	if h.next == len(h.samples) {
+		h.next = 0
+		h.full = true
+	}
+	h.mu.Unlock()
+}
+
+// Size returns the maximum number of samples retained.
+func (h *SlidingWindowHistogram) Size() int {
+	return len(h.samples)
+}
+
+// Count returns the number of samples currently in the window.
+func (h *SlidingWindowHistogram) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count()
+}
+
+// count returns the number of retained samples. Caller must hold h.mu.
+func (h *SlidingWindowHistogram) count() int {
+	if h.full {
+		return len(h.samples)
+	}
+	return h.next
+}
+
+// Quantile returns the q-quantile (0 <= q <= 1) of the samples in the window using
+// the nearest-rank method. Returns 0 if the window is empty; q is clamped to [0, 1].
+func (h *SlidingWindowHistogram) Quantile(q float64) int64 {
+	return h.Quantiles(q)[0]
+}
+
+// Quantiles returns several quantiles from a single sorted copy of the window.
+func (h *SlidingWindowHistogram) Quantiles(qs ...float64) []int64 {
+	sorted := h.sortedSnapshot()
+	result := make([]int64, len(qs))
+	if len(sorted) == 0 {
+		return result
+	}
+	for i, q := range qs {
+		result[i] = nearestRank(sorted, q)
+	}
+	return result
+}
+
+// RenderQuantiles outputs the window as a Prometheus summary-style block
+// (quantile series plus _count), e.g. for a status page:
+//
+//	http_request_duration_ms{quantile="0.5"} 12
+//	http_request_duration_ms{quantile="0.99"} 87
+//	http_request_duration_ms_count 1024
+//
+// _count is the number of samples in the window, not a cumulative total.
+func (h *SlidingWindowHistogram) RenderQuantiles(metric string, qs ...float64) string {
+	sorted := h.sortedSnapshot()
+
+	sb := &strings.Builder{}
+	for _, q := range qs {
+		var v int64
+		if len(sorted) > 0 {
+			v = nearestRank(sorted, q)
+		}
+		fmt.Fprintf(sb, "%s{quantile=\"%s\"} %d\n", metric, strconv.FormatFloat(q, 'g', -1, 64), v)
+	}
+	fmt.Fprintf(sb, "%s_count %d\n", metric, len(sorted))
+
+	return sb.String()
+}
+
+// Reset discards all samples in the window.
+func (h *SlidingWindowHistogram) Reset() {
+	h.mu.Lock()
+	h.next = 0
+	h.full = false
+	h.mu.Unlock()
+}
+
+// sortedSnapshot returns a sorted copy of the retained samples.
+func (h *SlidingWindowHistogram) sortedSnapshot() []int64 {
+	h.mu.Lock()
+	sorted := make([]int64, h.count())
+	copy(sorted, h.samples[:len(sorted)])
+	h.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// nearestRank returns the q-quantile of a non-empty sorted slice.
+func nearestRank(sorted []int64, q float64) int64 {
+	if q <= 0 || math.IsNaN(q) {
+		return sorted[0]
+	}
+	if q >= 1 {
+		return sorted[len(sorted)-1]
+	}
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlidingWindowHistogram_Quantile(t *testing.T) {
+	h := NewSlidingWindowHistogram(100)
+	for i := int64(1); i <= 100; i++ {
+		h.Observe(i)
+	}
+
+	assert.Equal(t, int64(1), h.Quantile(0))
+	assert.Equal(t, int64(50), h.Quantile(0.5))
+	assert.Equal(t, int64(99), h.Quantile(0.99))
+	assert.Equal(t, int64(100), h.Quantile(1))
+}
+
+func TestSlidingWindowHistogram_EvictsOldest(t *testing.T) {
+	h := NewSlidingWindowHistogram(3)
+	for _, v := range []int64{1000, 1, 2, 3} {
+		h.Observe(v)
+	}
+
+	assert.Equal(t, 3, h.Count())
+	assert.Equal(t, int64(3), h.Quantile(1))
+}
+
+func TestSlidingWindowHistogram_Empty(t *testing.T) {
+	h := NewSlidingWindowHistogram(0)
+
+	assert.Equal(t, DefaultWindowSize, h.Size())
+	assert.Equal(t, int64(0), h.Quantile(0.99))
+
+	h.Observe(5)
+	h.Reset()
+	assert.Equal(t, 0, h.Count())
+}
+
+func TestSlidingWindowHistogram_RenderQuantiles(t *testing.T) {
+	h := NewSlidingWindowHistogram(10)
+	for i := int64(1); i <= 10; i++ {
+		h.Observe(i)
+	}
+
+	out := h.RenderQuantiles("http_request_duration_ms", 0.5, 0.99)
+	assert.True(t, strings.Contains(out, `http_request_duration_ms{quantile="0.5"} 5`))
+	assert.True(t, strings.Contains(out, `http_request_duration_ms{quantile="0.99"} 10`))
+	assert.True(t, strings.Contains(out, "http_request_duration_ms_count 10"))
+}
@@ -0,0 +1,208 @@
+package util
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker.Execute when the call is rejected
+// because the breaker is open (or half-open with its trial calls in flight).
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+// Values are stable (0 closed, 1 open, 2 half-open) so they can be exported as a gauge.
+type BreakerState int
+
+const (
+	// BreakerClosed lets calls through and counts consecutive failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects calls with ErrCircuitOpen until OpenTimeout elapses.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of trial calls through to probe recovery.
+	BreakerHalfOpen
+)
+
+// String returns the state name ("closed", "open", "half-open").
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig configures a CircuitBreaker.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that trips the breaker (default: 5)
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before allowing trial calls (default: 30s)
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is the number of concurrent trial calls allowed while half-open (default: 1)
+	HalfOpenMaxCalls int
+	// OnStateChange is called after every state transition (default: nil)
+	// It runs while the breaker's lock is held, so it must be fast and must not call back into the breaker.
+	OnStateChange func(from, to BreakerState)
+}
+
+// CircuitBreaker stops calling a failing dependency for a while so failures don't cascade.
+// It is safe for concurrent use.
+//
+// Behavior:
+// - Closed: calls run; FailureThreshold consecutive failures open the breaker
+// - Open: calls fail fast with ErrCircuitOpen until OpenTimeout elapses
+// - Half-open: up to HalfOpenMaxCalls trial calls run; a success closes the breaker, a failure reopens it
+type CircuitBreaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int       // Consecutive failures while closed
+	openedAt time.Time // When the breaker last opened
+	inFlight int       // Trial calls running while half-open
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker.
+//
+// Example usage:
+//
+//	cb := util.NewCircuitBreaker(util.BreakerConfig{
+//	    FailureThreshold: 5,
+//	    OpenTimeout:      30 * time.Second,
+//	    OnStateChange: func(from, to util.BreakerState) {
+//	        logger.Warn("vendor breaker", zap.Stringer("from", from), zap.Stringer("to", to))
+//	    },
+//	})
+//
+//	err := cb.Execute(func() error {
+//	    return vendor.Call(ctx)
+//	})
+//	if errors.Is(err, util.ErrCircuitOpen) {
+//	    // Serve a fallback
+//	}
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = 1
+	}
+
+	return &CircuitBreaker{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Execute runs fn if the breaker allows it and records the outcome.
+// Returns ErrCircuitOpen without calling fn when the breaker rejects the call;
+// otherwise returns fn's error. A panic in fn counts as a failure and is re-raised.
+func (b *CircuitBreaker) Execute(fn func() error) (err error) {
+	trial, ok := b.allow()
+	if !ok {
+		return ErrCircuitOpen
+	}
+
+	succeeded := false
+	defer func() {
+		b.record(trial, succeeded)
+	}()
+
+	err = fn()
+	succeeded = err == nil
+	return err
+}
+
+// State returns the current state, moving from open to half-open if OpenTimeout has elapsed.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkTimeout()
+	return b.state
+}
+
+// allow reports whether a call may run and whether it is a half-open trial call.
+func (b *CircuitBreaker) allow() (trial, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkTimeout()
+
+	switch b.state {
+	case BreakerOpen:
+		return false, false
+	case BreakerHalfOpen:
+		if b.inFlight >= b.cfg.HalfOpenMaxCalls {
+			return false, false
+		}
+		b.inFlight++
+		return true, true
+	default:
+		return false, true
+	}
+}
+
+// record updates the breaker with the outcome of a call.
+func (b *CircuitBreaker) record(trial, succeeded bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.inFlight--
+	}
+
+	if succeeded {
+		b.failures = 0
+		if b.state == BreakerHalfOpen {
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+
+	switch b.state {
+	case BreakerHalfOpen:
+		b.open()
+	case BreakerClosed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+// open trips the breaker. Caller must hold b.mu.
+func (b *CircuitBreaker) open() {
+	b.failures = 0
+	b.openedAt = b.now()
+	b.setState(BreakerOpen)
+}
+
+// checkTimeout moves an open breaker to half-open once OpenTimeout has elapsed.
+// Caller must hold b.mu.
+func (b *CircuitBreaker) checkTimeout() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+// setState transitions to state and notifies OnStateChange. Caller must hold b.mu.
+func (b *CircuitBreaker) setState(state BreakerState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, state)
+	}
+}
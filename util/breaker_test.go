@@ -0,0 +1,96 @@
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errVendor = errors.New("vendor down")
+
+// newTestBreaker returns a breaker with a controllable clock.
+func newTestBreaker(cfg BreakerConfig) (*CircuitBreaker, *time.Time) {
+	now := time.Unix(0, 0)
+	cb := NewCircuitBreaker(cfg)
+	cb.now = func() time.Time { return now }
+	return cb, &now
+}
+
+func TestCircuitBreakerTripsAfterThreshold(t *testing.T) {
+	cb, _ := newTestBreaker(BreakerConfig{FailureThreshold: 3})
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, cb.Execute(func() error { return errVendor }), errVendor)
+	}
+	assert.Equal(t, BreakerOpen, cb.State())
+
+	called := false
+	err := cb.Execute(func() error { called = true; return nil })
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, called)
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	cb, _ := newTestBreaker(BreakerConfig{FailureThreshold: 2})
+
+	_ = cb.Execute(func() error { return errVendor })
+	_ = cb.Execute(func() error { return nil })
+	_ = cb.Execute(func() error { return errVendor })
+
+	assert.Equal(t, BreakerClosed, cb.State())
+}
+
+func TestCircuitBreakerHalfOpenRecovery(t *testing.T) {
+	var transitions []string
+	cb, now := newTestBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Second,
+		OnStateChange: func(from, to BreakerState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	_ = cb.Execute(func() error { return errVendor })
+	assert.Equal(t, BreakerOpen, cb.State())
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, BreakerHalfOpen, cb.State())
+
+	// Failed trial reopens
+	_ = cb.Execute(func() error { return errVendor })
+	assert.Equal(t, BreakerOpen, cb.State())
+
+	*now = now.Add(time.Second)
+	assert.NoError(t, cb.Execute(func() error { return nil }))
+	assert.Equal(t, BreakerClosed, cb.State())
+
+	assert.Equal(t, []string{
+		"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreakerHalfOpenLimitsTrials(t *testing.T) {
+	cb, now := newTestBreaker(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Second})
+
+	_ = cb.Execute(func() error { return errVendor })
+	*now = now.Add(time.Second)
+
+	err := cb.Execute(func() error {
+		// A second call while the trial is in flight is rejected
+		assert.ErrorIs(t, cb.Execute(func() error { return nil }), ErrCircuitOpen)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, cb.State())
+}
+
+func TestCircuitBreakerPanicCountsAsFailure(t *testing.T) {
+	cb, _ := newTestBreaker(BreakerConfig{FailureThreshold: 1})
+
+	assert.Panics(t, func() {
+		_ = cb.Execute(func() error { panic("boom") })
+	})
+	assert.Equal(t, BreakerOpen, cb.State())
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// WithBudget derives a context for downstream calls (databases, caches, other
+// services) whose deadline is the parent's deadline minus reserve, so a handler
+// bounded by a request timeout still has reserve left to write a response when a
+// downstream call runs out of time.
+//
+// Behaviour:
+// - If parent has no deadline, parent is returned unchanged with a no-op cancel func
+// - If less than reserve remains, the returned context is already done with context.DeadlineExceeded, so downstream calls fail fast
+// - Cancelling parent still cancels the returned context
+//
+// As with context.WithDeadline, call cancel once the downstream work is done.
+//
+// Example usage:
+//
+//	ctx, cancel := util.WithBudget(c.UserContext(), 50*time.Millisecond)
+//	defer cancel()
+//	rows, err := db.QueryContext(ctx, query)
+func WithBudget(parent context.Context, reserve time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := parent.Deadline()
+	if !ok {
+		return parent, func() {}
+	}
+	return context.WithDeadline(parent, deadline.Add(-reserve))
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBudget(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+
+	ctx, cancel := WithBudget(parent, 200*time.Millisecond)
+	defer cancel()
+
+	parentDeadline, _ := parent.Deadline()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, parentDeadline.Add(-200*time.Millisecond), deadline)
+
+	cancelParent()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestWithBudgetNoDeadline(t *testing.T) {
+	parent := context.Background()
+	ctx, cancel := WithBudget(parent, time.Second)
+	defer cancel()
+
+	assert.Equal(t, parent, ctx)
+}
+
+func TestWithBudgetExhausted(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelParent()
+
+	ctx, cancel := WithBudget(parent, time.Second)
+	defer cancel()
+
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	assert.NoError(t, parent.Err())
+}
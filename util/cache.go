@@ -0,0 +1,226 @@
+package util
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is an in-memory key/value cache with per-entry TTLs, safe for concurrent use.
+// It supports:
+// - Lazy expiry: expired entries are never returned and are removed on access
+// - Optional background janitor for removing expired entries proactively
+// - Optional size bound with least-recently-used eviction
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	entries    map[K]*list.Element
+	lru        *list.List // Front = most recently used
+	maxEntries int        // <= 0 means unbounded
+	now        func() time.Time
+	loads      Once[K, V] // Deduplicates concurrent GetOrLoad misses
+
+	// Background janitor state (nil when not running)
+	janitorStop chan struct{}
+	janitorDone chan struct{}
+}
+
+// cacheEntry is a cached value and its expiry (zero = never expires).
+type cacheEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// expired reports whether the entry has expired at now.
+func (e *cacheEntry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// NewCache creates a cache holding at most maxEntries entries; the least recently
+// used entry is evicted when full. Use maxEntries <= 0 for an unbounded cache.
+//
+// Example usage:
+//
+//	plans := util.NewCache[string, int](10000)
+//	rate, err := plans.GetOrLoad(tenantID, 5*time.Minute, func() (int, error) {
+//	    return db.RateForTenant(ctx, tenantID)
+//	})
+func NewCache[K comparable, V any](maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{
+		entries:    make(map[K]*list.Element),
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get returns the value for key if present and not expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*cacheEntry[K, V])
+	if e.expired(c.now()) {
+		c.removeElement(el)
+		var zero V
+		return zero, false
+	}
+	c.lru.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value for key, expiring after ttl. A ttl <= 0 never expires.
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*cacheEntry[K, V])
+		e.value = value
+		e.expires = expires
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	if c.maxEntries > 0 && c.lru.Len() >= c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry[K, V]{key: key, value: value, expires: expires})
+}
+
+// GetOrLoad returns the cached value for key, or calls loader and caches its result
+// for ttl. Errors from loader are returned and not cached. Concurrent misses for the
+// same key share a single loader call and all receive its result or error; the
+// loader runs without holding the cache lock, so other keys are not blocked.
+func (c *Cache[K, V]) GetOrLoad(key K, ttl time.Duration, loader func() (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	return c.loads.GetOrLoad(key, func() (V, error) {
+		// A load that finished just before this one started has already cached the value
+		if v, ok := c.Get(key); ok {
+			return v, nil
+		}
+		v, err := loader()
+		if err == nil {
+			c.Set(key, v, ttl)
+		}
+		return v, err
+	})
+}
+
+// Delete removes key from the cache. Returns false if it was not present.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.removeElement(el)
+	return true
+}
+
+// Len returns the number of entries, including expired ones not yet removed.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Clear removes all entries.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[K]*list.Element)
+	c.lru.Init()
+}
+
+// StartJanitor starts a background goroutine that removes expired entries every
+// interval (default: 1 minute if <= 0). Without it, expired entries are removed
+// lazily on access or evicted when the cache is full.
+// Calling StartJanitor while a janitor is already running is a no-op.
+//
+// Example usage:
+//
+//	cache := util.NewCache[string, []byte](0)
+//	cache.StartJanitor(time.Minute)
+//	defer cache.Close()
+func (c *Cache[K, V]) StartJanitor(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.janitorStop != nil {
+		return
+	}
+	c.janitorStop = make(chan struct{})
+	c.janitorDone = make(chan struct{})
+	go c.janitor(interval, c.janitorStop, c.janitorDone)
+}
+
+// Close stops the background janitor and waits for it to exit.
+// It is safe to call Close multiple times or when no janitor is running.
+func (c *Cache[K, V]) Close() {
+	c.mu.Lock()
+	stop, done := c.janitorStop, c.janitorDone
+	c.janitorStop, c.janitorDone = nil, nil
+	c.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// janitor periodically removes expired entries until stop is closed.
+func (c *Cache[K, V]) janitor(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.removeExpired()
+		}
+	}
+}
+
+// removeExpired removes all expired entries.
+func (c *Cache[K, V]) removeExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for _, el := range c.entries {
+		if el.Value.(*cacheEntry[K, V]).expired(now) {
+			c.removeElement(el)
+		}
+	}
+}
+
+// removeElement removes el from the list and index. Caller must hold c.mu.
+func (c *Cache[K, V]) removeElement(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry[K, V]).key)
+}
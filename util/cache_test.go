@@ -0,0 +1,141 @@
+package util
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCache returns a cache with a controllable clock.
+func newTestCache(maxEntries int) (*Cache[string, int], *time.Time) {
+	now := time.Unix(0, 0)
+	c := NewCache[string, int](maxEntries)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCacheGetSet(t *testing.T) {
+	c, _ := newTestCache(0)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1, time.Minute)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	c.Set("a", 2, time.Minute)
+	v, _ = c.Get("a")
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestCacheExpiresLazily(t *testing.T) {
+	c, now := newTestCache(0)
+
+	c.Set("a", 1, time.Minute)
+	c.Set("forever", 2, 0)
+
+	*now = now.Add(time.Minute)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len(), "expired entry should be removed on access")
+
+	v, ok := c.Get("forever")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := newTestCache(2)
+
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	c.Get("a") // b is now least recently used
+	c.Set("c", 3, 0)
+
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	c, now := newTestCache(0)
+	calls := 0
+	loader := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	v, err := c.GetOrLoad("a", time.Minute, loader)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	v, _ = c.GetOrLoad("a", time.Minute, loader)
+	assert.Equal(t, 1, v, "cached value should be returned")
+
+	*now = now.Add(time.Minute)
+	v, _ = c.GetOrLoad("a", time.Minute, loader)
+	assert.Equal(t, 2, v, "expired value should be reloaded")
+}
+
+func TestCacheGetOrLoadDoesNotCacheErrors(t *testing.T) {
+	c, _ := newTestCache(0)
+	errLoad := errors.New("db down")
+
+	_, err := c.GetOrLoad("a", time.Minute, func() (int, error) { return 0, errLoad })
+	assert.ErrorIs(t, err, errLoad)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCacheDeleteAndClear(t *testing.T) {
+	c, _ := newTestCache(0)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+
+	assert.True(t, c.Delete("a"))
+	assert.False(t, c.Delete("a"))
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCacheJanitorRemovesExpired(t *testing.T) {
+	c := NewCache[string, int](0)
+	c.Set("a", 1, time.Millisecond)
+	c.Set("b", 2, 0)
+
+	c.StartJanitor(5 * time.Millisecond)
+	defer c.Close()
+
+	assert.Eventually(t, func() bool { return c.Len() == 1 }, time.Second, 5*time.Millisecond)
+
+	c.Close()
+	c.Close() // idempotent
+}
+
+func TestCacheConcurrentAccess(t *testing.T) {
+	c := NewCache[int, int](50)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				c.Set(j, i, time.Minute)
+				c.Get(j)
+				_, _ = c.GetOrLoad(j+1000, time.Minute, func() (int, error) { return j, nil })
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, c.Len(), 50)
+}
@@ -0,0 +1,41 @@
+package util
+
+import "strings"
+
+// Coalesce returns the first value that is not the zero value of T, or the zero
+// value if all are zero. Zero means: "" for strings, 0 for numbers, false for
+// bools, nil for pointers, and the all-zero struct for comparable structs
+// (e.g. time.Time{}).
+//
+// Because zero values are skipped, Coalesce cannot express "explicitly set to
+// zero": Coalesce(0, 8080) is 8080. Use it only where zero means "unset".
+//
+// Example usage:
+//
+//	port := util.Coalesce(flagPort, cfg.GetInt("server.port"), 8080)
+//	timeout := util.Coalesce(override, cfg.GetDuration("http.timeout"), 30*time.Second)
+func Coalesce[T comparable](vals ...T) T {
+	var zero T
+	for _, v := range vals {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
+// FirstNonEmpty returns the first string that contains non-whitespace characters,
+// or "" if none do. Unlike Coalesce, whitespace-only strings (e.g. a header set
+// to " ") are treated as empty. The chosen value is returned unmodified.
+//
+// Example usage:
+//
+//	host := util.FirstNonEmpty(os.Getenv("DB_HOST"), cfg.GetString("database.host"), "localhost")
+func FirstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesce(t *testing.T) {
+	assert.Equal(t, 8080, Coalesce(0, 8080, 9090))
+	assert.Equal(t, "a", Coalesce("", "a", "b"))
+	assert.Equal(t, 0, Coalesce(0, 0))
+	assert.Equal(t, 0, Coalesce[int]())
+	assert.Equal(t, 5*time.Second, Coalesce(time.Duration(0), 5*time.Second))
+	assert.True(t, Coalesce(false, true))
+
+	now := time.Now()
+	assert.Equal(t, now, Coalesce(time.Time{}, now))
+}
+
+func TestFirstNonEmpty(t *testing.T) {
+	assert.Equal(t, "b", FirstNonEmpty("", "  ", "b", "c"))
+	assert.Equal(t, " x ", FirstNonEmpty(" x ", "y"))
+	assert.Equal(t, "", FirstNonEmpty("", " "))
+	assert.Equal(t, "", FirstNonEmpty())
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"time"
+
+	"github.com/cubetiqlabs/gopkg/types"
+)
+
+// Date range presets for reporting quick-selects. Each range starts at midnight in
+// the given location and ends inclusively at the last nanosecond of its final day,
+// so queries can use start <= t && t <= end. Boundaries are computed with calendar
+// arithmetic, so days shortened or lengthened by DST transitions are handled. A nil
+// location means time.Local.
+
+// Today returns the range covering the current day in loc.
+//
+// Example usage:
+//
+//	loc, _ := time.LoadLocation("Asia/Phnom_Penh")
+//	r := util.Today(loc)
+//	orders, err := repo.OrdersBetween(ctx, r.StartDate, r.EndDate)
+func Today(loc *time.Location) types.DateRange {
+	return lastNDaysAt(time.Now(), 1, loc)
+}
+
+// LastNDays returns the n days ending today, including today: LastNDays(7, loc)
+// starts at midnight six days ago. n < 1 is treated as 1.
+func LastNDays(n int, loc *time.Location) types.DateRange {
+	return lastNDaysAt(time.Now(), n, loc)
+}
+
+// ThisWeek returns the current week in loc, starting on weekStart (e.g. time.Monday)
+// and covering seven days.
+func ThisWeek(loc *time.Location, weekStart time.Weekday) types.DateRange {
+	return thisWeekAt(time.Now(), loc, weekStart)
+}
+
+// ThisMonth returns the current calendar month in loc, from the 1st to its last day.
+func ThisMonth(loc *time.Location) types.DateRange {
+	return thisMonthAt(time.Now(), loc)
+}
+
+// lastNDaysAt implements Today and LastNDays relative to now.
+func lastNDaysAt(now time.Time, n int, loc *time.Location) types.DateRange {
+	if n < 1 {
+		n = 1
+	}
+	y, m, d := inLocation(now, loc).Date()
+	return daysRange(y, m, d-(n-1), n, loc)
+}
+
+// thisWeekAt implements ThisWeek relative to now.
+func thisWeekAt(now time.Time, loc *time.Location, weekStart time.Weekday) types.DateRange {
+	local := inLocation(now, loc)
+	offset := (int(local.Weekday()) - int(weekStart) + 7) % 7
+	y, m, d := local.Date()
+	return daysRange(y, m, d-offset, 7, loc)
+}
+
+// thisMonthAt implements ThisMonth relative to now.
+func thisMonthAt(now time.Time, loc *time.Location) types.DateRange {
+	y, m, _ := inLocation(now, loc).Date()
+	start := time.Date(y, m, 1, 0, 0, 0, 0, location(loc))
+	return types.DateRange{
+		StartDate: start,
+		EndDate:   time.Date(y, m+1, 1, 0, 0, 0, 0, location(loc)).Add(-time.Nanosecond),
+	}
+}
+
+// daysRange returns the range of days calendar days starting on y-m-d in loc.
+// time.Date normalizes out-of-range days, e.g. March 0 is the last day of February.
+func daysRange(y int, m time.Month, d, days int, loc *time.Location) types.DateRange {
+	return types.DateRange{
+		StartDate: time.Date(y, m, d, 0, 0, 0, 0, location(loc)),
+		EndDate:   time.Date(y, m, d+days, 0, 0, 0, 0, location(loc)).Add(-time.Nanosecond),
+	}
+}
+
+// inLocation returns t in loc (time.Local if nil).
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return t.In(location(loc))
+}
+
+// location returns loc, or time.Local if nil.
+func location(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.Local
+	}
+	return loc
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangePresets(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Phnom_Penh") // UTC+7
+	require.NoError(t, err)
+
+	// 2025-03-05 20:00 UTC is already Thursday 2025-03-06 in Phnom Penh
+	now := time.Date(2025, 3, 5, 20, 0, 0, 0, time.UTC)
+	endOf := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 23, 59, 59, 999999999, loc)
+	}
+
+	today := lastNDaysAt(now, 1, loc)
+	assert.Equal(t, time.Date(2025, 3, 6, 0, 0, 0, 0, loc), today.StartDate)
+	assert.Equal(t, endOf(2025, 3, 6), today.EndDate)
+
+	// Crosses into February
+	last7 := lastNDaysAt(now, 7, loc)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, loc), last7.StartDate)
+	assert.Equal(t, endOf(2025, 3, 6), last7.EndDate)
+	assert.Equal(t, today, lastNDaysAt(now, 0, loc))
+
+	week := thisWeekAt(now, loc, time.Monday)
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, loc), week.StartDate)
+	assert.Equal(t, endOf(2025, 3, 9), week.EndDate)
+
+	sundayWeek := thisWeekAt(now, loc, time.Sunday)
+	assert.Equal(t, time.Date(2025, 3, 2, 0, 0, 0, 0, loc), sundayWeek.StartDate)
+
+	thursdayWeek := thisWeekAt(now, loc, time.Thursday)
+	assert.Equal(t, today.StartDate, thursdayWeek.StartDate)
+
+	month := thisMonthAt(now, loc)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, loc), month.StartDate)
+	assert.Equal(t, endOf(2025, 3, 31), month.EndDate)
+}
+
+func TestDateRangePresetsAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Clocks spring forward on 2025-03-09, a 23-hour day
+	now := time.Date(2025, 3, 9, 12, 0, 0, 0, loc)
+	today := lastNDaysAt(now, 1, loc)
+	assert.Equal(t, 23*time.Hour-time.Nanosecond, today.EndDate.Sub(today.StartDate))
+
+	last3 := lastNDaysAt(now, 3, loc)
+	assert.Equal(t, time.Date(2025, 3, 7, 0, 0, 0, 0, loc), last3.StartDate)
+	assert.Equal(t, 0, last3.StartDate.Hour())
+}
+
+func TestTodayDefaultsToLocal(t *testing.T) {
+	r := Today(nil)
+	assert.Equal(t, time.Local, r.StartDate.Location())
+	assert.False(t, time.Now().Before(r.StartDate))
+	assert.False(t, time.Now().After(r.EndDate))
+}
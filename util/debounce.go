@@ -0,0 +1,112 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// Debounce returns a trigger that coalesces bursts of calls: fn runs once, d after
+// the last trigger in a burst. Every trigger restarts the quiet period, so a
+// steady stream of triggers closer than d apart delays fn until the stream stops.
+// fn runs on its own goroutine, and runs never overlap; a trigger while fn is
+// running schedules one more run after the next quiet period.
+//
+// stop cancels a pending run and makes later triggers no-ops. It does not wait
+// for a run already in progress. Both functions are safe for concurrent use.
+//
+// Example usage:
+//
+//	reload, stop := util.Debounce(500*time.Millisecond, func() {
+//	    rebuildIndex() // Runs once after a burst of file events
+//	})
+//	defer stop()
+//	for range events {
+//	    reload()
+//	}
+func Debounce(d time.Duration, fn func()) (trigger func(), stop func()) {
+	var (
+		mu      sync.Mutex
+		run     sync.Mutex // Serializes fn
+		timer   *time.Timer
+		gen     uint64 // Incremented per trigger, so a superseded timer that already fired skips fn
+		stopped bool
+	)
+
+	trigger = func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if stopped {
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		gen++
+		scheduled := gen
+		timer = time.AfterFunc(d, func() {
+			run.Lock()
+			defer run.Unlock()
+
+			mu.Lock()
+			current := !stopped && scheduled == gen
+			mu.Unlock()
+			if current {
+				fn()
+			}
+		})
+	}
+
+	stop = func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stopped = true
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+	return trigger, stop
+}
+
+// Throttle returns a trigger that runs fn at most once per d, on the leading
+// edge: the first trigger runs fn immediately on the caller's goroutine, and
+// triggers during the following d are dropped (there is no trailing run).
+// Use Debounce instead when the last call's side effects must not be lost.
+//
+// stop makes later triggers no-ops. Both functions are safe for concurrent use;
+// concurrent triggers never run fn more than once per window.
+//
+// Example usage:
+//
+//	warn, stop := util.Throttle(time.Minute, func() {
+//	    logger.Warn("queue is full, dropping events")
+//	})
+//	defer stop()
+func Throttle(d time.Duration, fn func()) (trigger func(), stop func()) {
+	var (
+		mu      sync.Mutex
+		next    time.Time // Earliest time of the next run
+		stopped bool
+	)
+
+	trigger = func() {
+		mu.Lock()
+		now := time.Now()
+		if stopped || now.Before(next) {
+			mu.Unlock()
+			return
+		}
+		next = now.Add(d)
+		mu.Unlock()
+
+		fn()
+	}
+
+	stop = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		stopped = true
+	}
+	return trigger, stop
+}
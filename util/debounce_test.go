@@ -0,0 +1,56 @@
+package util
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebounceCoalescesBurst(t *testing.T) {
+	var calls int32
+	trigger, stop := Debounce(20*time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+	defer stop()
+
+	for i := 0; i < 5; i++ {
+		trigger()
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls), "should wait for the quiet period")
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDebounceStopCancelsPending(t *testing.T) {
+	var calls int32
+	trigger, stop := Debounce(10*time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+
+	trigger()
+	stop()
+	trigger()
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestThrottleLeadingEdge(t *testing.T) {
+	var calls int32
+	trigger, stop := Throttle(30*time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+
+	trigger()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "first trigger runs immediately")
+	trigger()
+	trigger()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	time.Sleep(40 * time.Millisecond)
+	trigger()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	stop()
+	time.Sleep(40 * time.Millisecond)
+	trigger()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,173 @@
+package util
+
+import "sync"
+
+// defaultEventBufferSize is the subscriber channel capacity used when EventBusConfig.BufferSize is 0.
+const defaultEventBufferSize = 16
+
+// Event is a message delivered to the subscribers of a topic.
+type Event struct {
+	Topic   string
+	Payload any
+}
+
+// EventBusConfig configures an EventBus.
+type EventBusConfig struct {
+	// BufferSize is the channel capacity of each subscriber (default: 16)
+	BufferSize int
+	// Block makes Publish wait for room in a full subscriber channel instead of
+	// dropping the event for that subscriber (default: false = drop)
+	// A slow subscriber then slows down every publisher of its topic.
+	Block bool
+	// OnDrop is called for each event dropped because a subscriber's channel was full (default: nil)
+	// It runs on the publishing goroutine, so it must be fast.
+	OnDrop func(Event)
+}
+
+// EventBus is an in-process publish/subscribe hub for decoupling components, e.g.
+// announcing "config.reloaded" or "tenant.created" to whoever is interested.
+// Each subscriber has its own buffered channel; by default Publish never blocks and
+// drops events for subscribers whose channel is full. It is safe for concurrent use.
+type EventBus struct {
+	cfg EventBusConfig
+
+	mu     sync.RWMutex
+	subs   map[string][]*subscriber
+	closed bool
+}
+
+// subscriber is one Subscribe call. done is closed on Unsubscribe to wake publishers
+// blocked on a full channel; mu keeps ch from being closed while a send is pending.
+type subscriber struct {
+	ch     chan Event
+	done   chan struct{}
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewEventBus creates an EventBus.
+//
+// Example usage:
+//
+//	bus := util.NewEventBus(util.EventBusConfig{BufferSize: 32})
+//	cfg.Watch(func() { bus.Publish("config.reloaded", nil) })
+//
+//	var perMinute atomic.Int32 // Read by a RateLimitConfig.RateGetter
+//	reloads := bus.Subscribe("config.reloaded")
+//	go func() {
+//	    for range reloads {
+//	        perMinute.Store(int32(cfg.GetInt("rate_limit.per_minute")))
+//	    }
+//	}()
+func NewEventBus(cfg EventBusConfig) *EventBus {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultEventBufferSize
+	}
+	return &EventBus{cfg: cfg, subs: make(map[string][]*subscriber)}
+}
+
+// Subscribe returns a channel receiving every event published to topic from now on.
+// The channel is closed by Unsubscribe or Close; after Close it is returned closed.
+func (b *EventBus) Subscribe(topic string) <-chan Event {
+	s := &subscriber{ch: make(chan Event, b.cfg.BufferSize), done: make(chan struct{})}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.close()
+		return s.ch
+	}
+	b.subs[topic] = append(b.subs[topic], s)
+	return s.ch
+}
+
+// Unsubscribe stops delivery to ch, a channel returned by Subscribe for topic, and
+// closes it. Events already buffered can still be received. Unknown channels are ignored.
+func (b *EventBus) Unsubscribe(topic string, ch <-chan Event) {
+	b.mu.Lock()
+	var removed *subscriber
+	subs := b.subs[topic]
+	for i, s := range subs {
+		if (<-chan Event)(s.ch) == ch {
+			removed = s
+			// Copy so publishers holding the old slice aren't affected
+			b.subs[topic] = append(append([]*subscriber(nil), subs[:i]...), subs[i+1:]...)
+			break
+		}
+	}
+	if len(b.subs[topic]) == 0 {
+		delete(b.subs, topic)
+	}
+	b.mu.Unlock()
+
+	if removed != nil {
+		removed.close()
+	}
+}
+
+// Publish delivers an event with payload to every current subscriber of topic.
+// Subscribers whose channel is full miss the event (see EventBusConfig.OnDrop),
+// unless EventBusConfig.Block is set. Publishing to a closed bus does nothing.
+func (b *EventBus) Publish(topic string, payload any) {
+	b.mu.RLock()
+	subs := b.subs[topic]
+	b.mu.RUnlock()
+
+	event := Event{Topic: topic, Payload: payload}
+	for _, s := range subs {
+		if !s.send(event, b.cfg.Block) && b.cfg.OnDrop != nil {
+			b.cfg.OnDrop(event)
+		}
+	}
+}
+
+// Close unsubscribes and closes every subscriber channel. Later Subscribe calls
+// return closed channels and Publish does nothing.
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	subs := b.subs
+	b.subs = make(map[string][]*subscriber)
+	b.closed = true
+	b.mu.Unlock()
+
+	for _, topicSubs := range subs {
+		for _, s := range topicSubs {
+			s.close()
+		}
+	}
+}
+
+// send delivers event, waiting for room if block is set. It returns false if the
+// event was dropped because the channel was full; events for closed subscribers are
+// discarded silently.
+func (s *subscriber) send(event Event, block bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return true
+	}
+
+	if block {
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		}
+		return true
+	}
+
+	select {
+	case s.ch <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// close wakes blocked senders, then closes the channel once no send is pending.
+func (s *subscriber) close() {
+	close(s.done)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.ch)
+}
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBusDeliversToSubscribers(t *testing.T) {
+	bus := NewEventBus(EventBusConfig{})
+	a := bus.Subscribe("tenant.created")
+	b := bus.Subscribe("tenant.created")
+	other := bus.Subscribe("config.reloaded")
+
+	bus.Publish("tenant.created", "acme")
+
+	for _, ch := range []<-chan Event{a, b} {
+		select {
+		case e := <-ch:
+			assert.Equal(t, Event{Topic: "tenant.created", Payload: "acme"}, e)
+		case <-time.After(time.Second):
+			t.Fatal("event not delivered")
+		}
+	}
+	assert.Empty(t, other)
+}
+
+func TestEventBusDropsWhenFull(t *testing.T) {
+	var dropped int32
+	bus := NewEventBus(EventBusConfig{
+		BufferSize: 2,
+		OnDrop:     func(Event) { atomic.AddInt32(&dropped, 1) },
+	})
+	ch := bus.Subscribe("t")
+
+	for i := 0; i < 5; i++ {
+		bus.Publish("t", i) // Must not block
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&dropped))
+	assert.Equal(t, 0, (<-ch).Payload)
+	assert.Equal(t, 1, (<-ch).Payload)
+}
+
+func TestEventBusBlockingUnsubscribeWakesPublisher(t *testing.T) {
+	bus := NewEventBus(EventBusConfig{BufferSize: 1, Block: true})
+	ch := bus.Subscribe("t")
+	bus.Publish("t", 1)
+
+	published := make(chan struct{})
+	go func() {
+		bus.Publish("t", 2) // Blocks: the buffer is full
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		t.Fatal("publish should block while the buffer is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	bus.Unsubscribe("t", ch)
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribe did not release the blocked publisher")
+	}
+
+	// Buffered events stay readable, then the channel is closed
+	e, ok := <-ch
+	require.True(t, ok)
+	assert.Equal(t, 1, e.Payload)
+	_, ok = <-ch
+	assert.False(t, ok)
+}
+
+func TestEventBusClose(t *testing.T) {
+	bus := NewEventBus(EventBusConfig{})
+	ch := bus.Subscribe("t")
+
+	bus.Close()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	bus.Publish("t", "ignored")
+	_, ok = <-bus.Subscribe("t")
+	assert.False(t, ok)
+}
+
+func TestEventBusConcurrentUse(t *testing.T) {
+	bus := NewEventBus(EventBusConfig{BufferSize: 4})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bus.Publish("t", j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			ch := bus.Subscribe("t")
+			for j := 0; j < 10; j++ {
+				select {
+				case <-ch:
+				default:
+				}
+			}
+			bus.Unsubscribe("t", ch)
+		}()
+	}
+	wg.Wait()
+	bus.Close()
+}
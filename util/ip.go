@@ -7,25 +7,17 @@ import (
 // GetClientIP extracts the real client IP from various headers and fallbacks.
 // Priority: CF-Connecting-IP > X-Real-IP > X-Forwarded-For > RemoteAddr
 func GetClientIP(c *fiber.Ctx) string {
-	// Cloudflare proxy: CF-Connecting-IP header contains the actual client IP
-	cfConnectingIP := c.Get("CF-Connecting-IP")
-	if cfConnectingIP != "" {
-		return cfConnectingIP
-	}
-
-	// Standard reverse proxy header
-	xRealIP := c.Get("X-Real-IP")
-	if xRealIP != "" {
-		return xRealIP
-	}
-
 	// X-Forwarded-For can contain multiple IPs (client, proxy1, proxy2...)
 	// The first IP is the original client
-	clientIPs := c.IPs()
-	if len(clientIPs) > 0 && clientIPs[0] != "" {
-		return clientIPs[0]
+	var forwarded string
+	if clientIPs := c.IPs(); len(clientIPs) > 0 {
+		forwarded = clientIPs[0]
 	}
 
-	// Fallback to Fiber's IP() method which uses RemoteAddr
-	return c.IP()
+	return FirstNonEmpty(
+		c.Get("CF-Connecting-IP"), // Cloudflare proxy: contains the actual client IP
+		c.Get("X-Real-IP"),        // Standard reverse proxy header
+		forwarded,
+		c.IP(), // Fallback to Fiber's IP() method which uses RemoteAddr
+	)
 }
@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -9,38 +10,92 @@ import (
 	"github.com/cubetiqlabs/gopkg/types"
 )
 
+// durationUnits maps lower-case ParseDuration units to their length.
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,     // Equivalent to 1 day
+	"w":  7 * 24 * time.Hour, // Equivalent to 1 week
+
+	// Verbose forms for human-authored config, e.g. "5 minutes"
+	"sec":     time.Second,
+	"secs":    time.Second,
+	"second":  time.Second,
+	"seconds": time.Second,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+}
+
+// ParseDuration parses a duration such as "10s", "1.5h", "4d", or "2w".
+// Supported units: ns, us (µs), ms, s, m, h, d, w. Verbose units are also accepted,
+// case-insensitively and optionally after a space: sec(s), second(s), min(s),
+// minute(s), hour(s), day(s), week(s), as in "5 minutes" or "1 Hour".
+// Negative values are rejected, since user-supplied timeouts and intervals are
+// expected to be positive; use ParseDurationAllowNegative for offsets. Values too
+// large for time.Duration (about 292 years) return an error instead of wrapping.
 func ParseDuration(input string) (time.Duration, error) {
-	unit := strings.TrimLeft(input, "0123456789.")
-	valueStr := strings.TrimSuffix(input, unit)
+	return parseDuration(input, false)
+}
+
+// ParseDurationAllowNegative is like ParseDuration but accepts a leading "-".
+func ParseDurationAllowNegative(input string) (time.Duration, error) {
+	return parseDuration(input, true)
+}
+
+// parseDuration implements ParseDuration and ParseDurationAllowNegative.
+func parseDuration(input string, allowNegative bool) (time.Duration, error) {
+	number := input
+	negative := false
+	switch {
+	case strings.HasPrefix(number, "-"):
+		negative = true
+		number = number[1:]
+	case strings.HasPrefix(number, "+"):
+		number = number[1:]
+	}
+	if negative && !allowNegative {
+		return 0, fmt.Errorf("negative duration not allowed: %q", input)
+	}
+
+	unit := strings.TrimLeft(number, "0123456789.")
+	valueStr := strings.TrimSuffix(number, unit)
 	if valueStr == "" {
 		return 0, fmt.Errorf("invalid duration format: %q", input)
 	}
+	unit = strings.TrimPrefix(unit, " ")
 
 	value, err := strconv.ParseFloat(valueStr, 64)
 	if err != nil {
 		return 0, fmt.Errorf("invalid duration value: %q", input)
 	}
 
-	switch strings.ToLower(unit) {
-	case "ns":
-		return time.Duration(value) * time.Nanosecond, nil
-	case "us", "µs":
-		return time.Duration(value) * time.Microsecond, nil
-	case "ms":
-		return time.Duration(value) * time.Millisecond, nil
-	case "s":
-		return time.Duration(value) * time.Second, nil
-	case "m":
-		return time.Duration(value) * time.Minute, nil
-	case "h":
-		return time.Duration(value) * time.Hour, nil
-	case "d":
-		return time.Duration(value) * time.Hour * 24, nil // Equivalent to 1 day
-	case "w":
-		return time.Duration(value) * time.Hour * 24 * 7, nil // Equivalent to 1 week
-	default:
+	size, ok := durationUnits[strings.ToLower(unit)]
+	if !ok {
 		return 0, fmt.Errorf("unknown unit: %q", unit)
 	}
+
+	// Multiply in float64 so fractions are kept and overflow can be detected
+	d := value * float64(size)
+	if d >= math.MaxInt64 {
+		return 0, fmt.Errorf("duration out of range: %q", input)
+	}
+	if negative {
+		d = -d
+	}
+	return time.Duration(d), nil
 }
 
 // ParseDateRange parses the start and end date strings into a DateRange struct.
@@ -42,6 +42,60 @@ func TestParseDuration(t *testing.T) {
 			want:    5 * 7 * 24 * time.Hour,
 			wantErr: false,
 		},
+		{
+			name:    "Test fractional hours",
+			input:   "1.5h",
+			want:    90 * time.Minute,
+			wantErr: false,
+		},
+		{
+			name:    "Test verbose minutes",
+			input:   "5 minutes",
+			want:    5 * time.Minute,
+			wantErr: false,
+		},
+		{
+			name:    "Test verbose singular hour",
+			input:   "1 Hour",
+			want:    time.Hour,
+			wantErr: false,
+		},
+		{
+			name:    "Test verbose days without space",
+			input:   "2days",
+			want:    2 * 24 * time.Hour,
+			wantErr: false,
+		},
+		{
+			name:    "Test verbose weeks",
+			input:   "1.5 WEEKS",
+			want:    time.Duration(1.5 * 7 * 24 * float64(time.Hour)),
+			wantErr: false,
+		},
+		{
+			name:    "Test verbose sec",
+			input:   "30 sec",
+			want:    30 * time.Second,
+			wantErr: false,
+		},
+		{
+			name:    "Test double space rejected",
+			input:   "5  minutes",
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name:    "Test negative rejected",
+			input:   "-5s",
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name:    "Test week overflow",
+			input:   "100000w",
+			want:    0,
+			wantErr: true,
+		},
 		{
 			name:    "Test invalid format",
 			input:   "invalid",
@@ -63,3 +117,14 @@ func TestParseDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDurationAllowNegative(t *testing.T) {
+	got, err := ParseDurationAllowNegative("-5s")
+	if err != nil || got != -5*time.Second {
+		t.Fatalf("ParseDurationAllowNegative(-5s) = %v, %v", got, err)
+	}
+
+	if _, err := ParseDurationAllowNegative("-100000w"); err == nil {
+		t.Fatal("expected overflow error for -100000w")
+	}
+}
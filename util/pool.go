@@ -0,0 +1,119 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Pool runs submitted functions with at most a fixed number running at once.
+// Cancelling the Pool's context stops it from accepting new work; functions that
+// already started run to completion. It is safe for concurrent use.
+type Pool struct {
+	ctx context.Context
+	sem chan struct{} // One slot per running function
+	wg  sync.WaitGroup
+}
+
+// NewPool creates a Pool running at most workers functions concurrently.
+// A workers value <= 0 is treated as 1.
+//
+// Example usage:
+//
+//	pool := util.NewPool(ctx, 8)
+//	for _, job := range jobs {
+//	    if err := pool.Submit(func() { process(job) }); err != nil {
+//	        break // ctx cancelled
+//	    }
+//	}
+//	pool.Wait()
+func NewPool(ctx context.Context, workers int) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Pool{ctx: ctx, sem: make(chan struct{}, workers)}
+}
+
+// Submit runs fn on the pool, blocking until a worker is free. It returns the
+// context's error without running fn if the Pool's context is done first.
+func (p *Pool) Submit(fn func()) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case p.sem <- struct{}{}:
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+		fn()
+	}()
+	return nil
+}
+
+// Wait blocks until every submitted function has returned.
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+// ForEach calls fn for each item with at most concurrency calls running at once,
+// and returns the first error. After an error no further items are started; calls
+// already running finish before ForEach returns. A concurrency <= 0 is treated as 1.
+//
+// Example usage:
+//
+//	err := util.ForEach(tenantIDs, 4, func(id string) error {
+//	    return syncTenant(ctx, id)
+//	})
+func ForEach[T any](items []T, concurrency int, fn func(T) error) error {
+	return forEach(items, concurrency, fn, true)
+}
+
+// ForEachAll is like ForEach but processes every item regardless of failures and
+// returns all errors joined with errors.Join (nil if every call succeeded).
+func ForEachAll[T any](items []T, concurrency int, fn func(T) error) error {
+	return forEach(items, concurrency, fn, false)
+}
+
+// forEach implements ForEach and ForEachAll.
+func forEach[T any](items []T, concurrency int, fn func(T) error, stopOnError bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	pool := NewPool(ctx, concurrency)
+	for _, item := range items {
+		if err := pool.Submit(func() {
+			if err := fn(item); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				if stopOnError {
+					cancel()
+				}
+			}
+		}); err != nil {
+			break // Stopped after an error
+		}
+	}
+	pool.Wait()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	if stopOnError {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolBoundsConcurrency(t *testing.T) {
+	pool := NewPool(context.Background(), 3)
+
+	var running, peak, done int32
+	for i := 0; i < 20; i++ {
+		require.NoError(t, pool.Submit(func() {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+		}))
+	}
+	pool.Wait()
+
+	assert.Equal(t, int32(20), done)
+	assert.LessOrEqual(t, peak, int32(3))
+}
+
+func TestPoolCancelStopsAcceptingWork(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewPool(ctx, 1)
+
+	release := make(chan struct{})
+	require.NoError(t, pool.Submit(func() { <-release }))
+
+	// The only worker is busy, so this Submit blocks until cancel
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	err := pool.Submit(func() { t.Error("should not run") })
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	pool.Wait()
+	assert.ErrorIs(t, pool.Submit(func() {}), context.Canceled)
+}
+
+func TestForEach(t *testing.T) {
+	var sum int64
+	err := ForEach([]int{1, 2, 3, 4}, 2, func(n int) error {
+		atomic.AddInt64(&sum, int64(n))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), sum)
+}
+
+func TestForEachStopsOnFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var calls int32
+
+	items := make([]int, 100)
+	err := ForEach(items, 1, func(int) error {
+		atomic.AddInt32(&calls, 1)
+		return errBoom
+	})
+
+	assert.ErrorIs(t, err, errBoom)
+	assert.Less(t, atomic.LoadInt32(&calls), int32(100))
+}
+
+func TestForEachAllCollectsErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	var calls int32
+
+	err := ForEachAll([]int{1, 2, 3, 4, 5}, 2, func(n int) error {
+		atomic.AddInt32(&calls, 1)
+		if n%2 == 1 {
+			return errOdd
+		}
+		return nil
+	})
+
+	assert.Equal(t, int32(5), calls)
+	assert.ErrorIs(t, err, errOdd)
+	assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"github.com/cubetiqlabs/gopkg/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SuccessResponse is the standard success envelope.
+// It complements middleware.ErrorResponse so clients see a consistent shape.
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data"`
+	Page    *types.Page `json:"page,omitempty"`
+}
+
+// SendSuccess writes data in a success envelope with 200 OK.
+//
+// Example:
+//
+//	return util.SendSuccess(c, user) // {"success":true,"data":{...}}
+func SendSuccess(c *fiber.Ctx, data interface{}) error {
+	return SendData(c, fiber.StatusOK, data)
+}
+
+// SendData writes data in a success envelope with the given status code.
+func SendData(c *fiber.Ctx, status int, data interface{}) error {
+	return c.Status(status).JSON(SuccessResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// SendPaginated writes a list in a success envelope with pagination metadata.
+//
+// Example:
+//
+//	return util.SendPaginated(c, users, types.NewPage(1, 20, total))
+func SendPaginated(c *fiber.Ctx, data interface{}, page types.Page) error {
+	return c.Status(fiber.StatusOK).JSON(SuccessResponse{
+		Success: true,
+		Data:    data,
+		Page:    &page,
+	})
+}
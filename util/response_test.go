@@ -0,0 +1,58 @@
+package util
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cubetiqlabs/gopkg/types"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendSuccess(t *testing.T) {
+	app := fiber.New()
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return SendSuccess(c, fiber.Map{"id": 1})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"success":true,"data":{"id":1}}`, string(body))
+}
+
+func TestSendData(t *testing.T) {
+	app := fiber.New()
+	app.Post("/test", func(c *fiber.Ctx) error {
+		return SendData(c, fiber.StatusCreated, "created")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/test", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"success":true,"data":"created"}`, string(body))
+}
+
+func TestSendPaginated(t *testing.T) {
+	app := fiber.New()
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return SendPaginated(c, []int{1, 2}, types.NewPage(1, 2, 5))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	require.NoError(t, err)
+
+	var out SuccessResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.True(t, out.Success)
+	require.NotNil(t, out.Page)
+	assert.Equal(t, 3, out.Page.TotalPages)
+	assert.Equal(t, int64(5), out.Page.Total)
+}
@@ -0,0 +1,138 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// signatureSeparator separates a signed value from its signature.
+const signatureSeparator = "."
+
+// Prefixes of the signed payload, so a signature made for a plain value can't be
+// replayed as a cookie, or vice versa.
+const (
+	valuePayloadPrefix  = "value\x00"
+	cookiePayloadPrefix = "cookie\x00"
+)
+
+// SignValue returns value with an HMAC-SHA256 signature appended, as
+// "<value>.<base64url signature>". The value itself is not encrypted; use it for
+// data the client may read but must not change, such as CSRF tokens or session IDs.
+// Panics if secret is empty.
+//
+// Example usage:
+//
+//	signed := util.SignValue(secret, sessionID)
+//	// later
+//	sessionID, ok := util.VerifyValue(secret, signed)
+func SignValue(secret, value string) string {
+	if secret == "" {
+		panic("util: SignValue requires a secret")
+	}
+	return value + signatureSeparator + sign(secret, valuePayloadPrefix+value)
+}
+
+// VerifyValue checks a value produced by SignValue and returns the original value.
+// ok is false if the signature is missing, malformed, or made with another secret.
+// Signatures are compared in constant time.
+func VerifyValue(secret, signed string) (string, bool) {
+	return VerifyValueAny([]string{secret}, signed)
+}
+
+// VerifyValueAny is like VerifyValue but accepts a signature made with any of
+// secrets. Use it to rotate keys: sign with the new secret and keep verifying with
+// the old one until every value signed with it has expired. Empty secrets are ignored.
+//
+// Example usage:
+//
+//	// Sign with secrets[0]; values signed with secrets[1] are still accepted
+//	secrets := []string{cfg.GetString("cookie.secret"), cfg.GetString("cookie.previous_secret")}
+//	value, ok := util.VerifyValueAny(secrets, signed)
+func VerifyValueAny(secrets []string, signed string) (string, bool) {
+	return verifySigned(secrets, signed, valuePayloadPrefix)
+}
+
+// verifySigned splits signed into value and signature and checks the signature
+// of prefix+value against each secret in turn.
+func verifySigned(secrets []string, signed, prefix string) (string, bool) {
+	i := strings.LastIndex(signed, signatureSeparator)
+	if i < 0 {
+		return "", false
+	}
+	value, sig := signed[:i], signed[i+1:]
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		if hmac.Equal([]byte(sig), []byte(sign(secret, prefix+value))) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// cookiePayload returns the signed payload prefix for the cookie name, so a
+// value signed for one cookie doesn't verify as another.
+func cookiePayload(name string) string {
+	return cookiePayloadPrefix + name + "\x00"
+}
+
+// sign returns the base64url (unpadded) HMAC-SHA256 of payload keyed by secret.
+func sign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SetSignedCookie sets cookie on the response with its value signed by secret.
+// The signature covers the cookie name as well, so the value can't be moved to
+// another cookie (e.g. from "csrf" to "session"). The cookie is otherwise sent as given, so set HTTPOnly, Secure, and SameSite as
+// appropriate. The value must be a valid cookie value (e.g. a URL-safe token).
+// Panics if secret is empty.
+//
+// Example usage:
+//
+//	util.SetSignedCookie(c, secret, &fiber.Cookie{
+//	    Name:     "csrf",
+//	    Value:    token,
+//	    HTTPOnly: true,
+//	    Secure:   true,
+//	    SameSite: fiber.CookieSameSiteStrictMode,
+//	})
+func SetSignedCookie(c *fiber.Ctx, secret string, cookie *fiber.Cookie) {
+	if secret == "" {
+		panic("util: SetSignedCookie requires a secret")
+	}
+	signed := *cookie
+	signed.Value = cookie.Value + signatureSeparator + sign(secret, cookiePayload(cookie.Name)+cookie.Value)
+	c.Cookie(&signed)
+}
+
+// SignedCookie returns the value of the request cookie name if its signature is
+// valid for that cookie name and any of secrets (see VerifyValueAny for rotation).
+// ok is false if the cookie is missing, has been tampered with, or was signed for
+// a different cookie.
+//
+// Example usage:
+//
+//	token, ok := util.SignedCookie(c, "csrf", currentSecret, previousSecret)
+//	if !ok {
+//	    return util.ForbiddenError("invalid CSRF token")
+//	}
+func SignedCookie(c *fiber.Ctx, name string, secrets ...string) (string, bool) {
+	signed := c.Cookies(name)
+	if signed == "" {
+		return "", false
+	}
+
+	value, ok := verifySigned(secrets, signed, cookiePayload(name))
+	if !ok {
+		return "", false
+	}
+	return strings.Clone(value), true // Cookies aliases a reused Fiber buffer
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerifyValue(t *testing.T) {
+	signed := SignValue("secret", "user.42")
+
+	value, ok := VerifyValue("secret", signed)
+	assert.True(t, ok)
+	assert.Equal(t, "user.42", value)
+
+	_, ok = VerifyValue("other", signed)
+	assert.False(t, ok)
+
+	_, ok = VerifyValue("secret", strings.Replace(signed, "42", "43", 1))
+	assert.False(t, ok)
+
+	_, ok = VerifyValue("secret", "no-signature")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { SignValue("", "value") })
+}
+
+func TestVerifyValueAnyRotatesSecrets(t *testing.T) {
+	signed := SignValue("old", "token")
+
+	value, ok := VerifyValueAny([]string{"new", "old"}, signed)
+	assert.True(t, ok)
+	assert.Equal(t, "token", value)
+
+	_, ok = VerifyValueAny([]string{"new", ""}, signed)
+	assert.False(t, ok)
+}
+
+func TestSignedCookie(t *testing.T) {
+	app := fiber.New()
+	app.Get("/set", func(c *fiber.Ctx) error {
+		SetSignedCookie(c, "secret", &fiber.Cookie{Name: "session", Value: "abc123", HTTPOnly: true})
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Get("/get", func(c *fiber.Ctx) error {
+		value, ok := SignedCookie(c, "session", "secret")
+		if !ok {
+			return ForbiddenError("invalid session")
+		}
+		return c.SendString(value)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/set", nil))
+	require.NoError(t, err)
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, strings.HasPrefix(cookies[0].Value, "abc123."))
+
+	req := httptest.NewRequest("GET", "/get", nil)
+	req.AddCookie(cookies[0])
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/get", nil)
+	req.Header.Set("Cookie", "session=abc124."+strings.SplitN(cookies[0].Value, ".", 2)[1])
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	// A value signed for another cookie, or with SignValue, is rejected
+	for _, forged := range []string{"session=" + strings.SplitN(signedCookieValue(t, "csrf", "abc123"), "=", 2)[1], "session=" + SignValue("secret", "abc123")} {
+		req = httptest.NewRequest("GET", "/get", nil)
+		req.Header.Set("Cookie", forged)
+		resp, err = app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, forged)
+	}
+}
+
+// signedCookieValue returns the Set-Cookie "name=value" pair written by SetSignedCookie.
+func signedCookieValue(t *testing.T, name, value string) string {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		SetSignedCookie(c, "secret", &fiber.Cookie{Name: name, Value: value})
+		return nil
+	})
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+	return cookies[0].Name + "=" + cookies[0].Value
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrLoaderPanicked is returned to callers waiting on a SingleFlight call whose
+// function panicked. The caller that ran the function sees the panic itself.
+var ErrLoaderPanicked = errors.New("singleflight: function panicked")
+
+// SingleFlight deduplicates concurrent calls by key: while a call for a key is in
+// flight, other callers for the same key wait for it and receive its result instead
+// of starting their own. Use it to stop a cache miss under load from fanning out into
+// many identical slow fetches (a thundering herd). The zero value is ready to use and
+// it is safe for concurrent use.
+//
+// Results are not cached; once a call returns, the next Do for its key runs fn again.
+// Once wraps it for callers that don't need to know whether a result was shared, and
+// Cache.GetOrLoad adds caching on top.
+type SingleFlight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+// flightCall is an in-flight or completed SingleFlight call.
+type flightCall[V any] struct {
+	wg      sync.WaitGroup
+	val     V
+	err     error
+	waiters int // Callers sharing the result, besides the one running fn
+}
+
+// Do runs fn for key, or waits for and returns the result of the call already in
+// flight for key. shared reports whether the result was given to more than one caller.
+//
+// Example usage:
+//
+//	var plans util.SingleFlight[string, Plan]
+//	plan, err, _ := plans.Do(tenantID, func() (Plan, error) {
+//	    return billing.FetchPlan(ctx, tenantID) // One fetch per tenant at a time
+//	})
+func (g *SingleFlight[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flightCall[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.waiters++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+
+	c := &flightCall[V]{err: ErrLoaderPanicked} // Replaced unless fn panics
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		shared = c.waiters > 0
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.val, c.err = fn()
+	return c.val, c.err, false
+}
+
+// Once shares in-flight loads by key: concurrent GetOrLoad calls for the same key run
+// load once and all receive its result or error. It is a SingleFlight for callers
+// that only need the value. The zero value is ready to use and it is safe for
+// concurrent use.
+//
+// Like SingleFlight, results are not retained; a GetOrLoad after the load returned
+// runs load again. Keep fresh values in a Cache (see Cache.GetOrLoad) to reuse them.
+type Once[K comparable, V any] struct {
+	flight SingleFlight[K, V]
+}
+
+// GetOrLoad runs load for key, or waits for the load already in flight for key and
+// returns its result. If load panics, the caller running it sees the panic and
+// waiting callers get ErrLoaderPanicked.
+//
+// Example usage:
+//
+//	var remote util.Once[string, map[string]interface{}]
+//	settings, err := remote.GetOrLoad(url, func() (map[string]interface{}, error) {
+//	    return fetchSettings(ctx, url) // One request per URL, however many callers miss
+//	})
+func (o *Once[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	v, err, _ := o.flight.Do(key, load)
+	return v, err
+}
@@ -0,0 +1,135 @@
+package util
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleFlightSharesInFlightCall(t *testing.T) {
+	var g SingleFlight[string, int]
+	var calls int32
+	release := make(chan struct{})
+	errShared := errors.New("shared")
+
+	var wg sync.WaitGroup
+	results := make([]error, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, results[i], _ = g.Do("k", func() (int, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return 0, errShared
+			})
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond) // Let every caller join the flight
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, err := range results {
+		assert.ErrorIs(t, err, errShared)
+	}
+
+	// Completed calls aren't cached
+	v, err, shared := g.Do("k", func() (int, error) { return 7, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 7, v)
+	assert.False(t, shared)
+}
+
+func TestSingleFlightPanicReleasesWaiters(t *testing.T) {
+	var g SingleFlight[string, int]
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	go func() {
+		defer func() { _ = recover() }()
+		_, _, _ = g.Do("k", func() (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+
+	<-started
+	done := make(chan error)
+	go func() {
+		_, err, _ := g.Do("k", func() (int, error) { return 1, nil })
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-done:
+		// The waiter either joined the panicking call or ran after it
+		if err != nil {
+			assert.ErrorIs(t, err, ErrLoaderPanicked)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter blocked after panic")
+	}
+}
+
+func TestOnceGetOrLoadSharesResult(t *testing.T) {
+	var once Once[string, int]
+	var calls int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := once.GetOrLoad("plan", func() (int, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return 600, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 600, v)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // Let every caller join the load
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Results aren't retained
+	v, err := once.GetOrLoad("plan", func() (int, error) { return 1200, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1200, v)
+}
+
+func TestCacheGetOrLoadDeduplicatesMisses(t *testing.T) {
+	c := NewCache[string, int](0)
+	var calls int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad("plan", time.Minute, func() (int, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(20 * time.Millisecond)
+				return 600, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 600, v)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
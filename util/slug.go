@@ -0,0 +1,69 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Slugify converts s into a URL slug: accents are stripped, letters are lowercased,
+// and every run of other characters becomes a single dash, with no leading or
+// trailing dashes. Only ASCII letters and digits are kept, so text without a Latin
+// decomposition (e.g. Khmer or CJK) yields "".
+//
+// Example usage:
+//
+//	util.Slugify("  Crème Brûlée -- Recipe! ") // "creme-brulee-recipe"
+//	util.Slugify("Tenant_42 (Prod)")           // "tenant-42-prod"
+func Slugify(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	dash := false
+
+	// NFD splits accented letters into base letter + combining mark
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// SanitizeLabelValue makes s safe to use verbatim as a Prometheus label value:
+// double quotes, backslashes, control characters (including newlines), and invalid
+// UTF-8 are replaced with '_', and surrounding whitespace is trimmed. Other Unicode
+// is kept, since Prometheus label values may be any UTF-8.
+//
+// Example usage:
+//
+//	reg.IncLabeled("signups", map[string]string{
+//	    "plan": util.SanitizeLabelValue(req.PlanName),
+//	})
+func SanitizeLabelValue(s string) string {
+	s = strings.TrimSpace(s)
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if (r == utf8.RuneError && size <= 1) || r == '"' || r == '\\' || unicode.IsControl(r) {
+			b.WriteByte('_')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Crème Brûlée -- Recipe! ", "creme-brulee-recipe"},
+		{"Tenant_42 (Prod)", "tenant-42-prod"},
+		{"already-a-slug", "already-a-slug"},
+		{"Ünïcödé", "unicode"},
+		{"---", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Slugify(tt.in), "Slugify(%q)", tt.in)
+	}
+}
+
+func TestSanitizeLabelValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"pro", "pro"},
+		{` say "hi" `, `say _hi_`},
+		{"line1\nline2", "line1_line2"},
+		{`C:\path`, "C:_path"},
+		{"caf\xe9", "caf_"},
+		{"café", "café"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, SanitizeLabelValue(tt.in), "SanitizeLabelValue(%q)", tt.in)
+	}
+}
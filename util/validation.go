@@ -0,0 +1,75 @@
+package util
+
+import (
+	"strings"
+)
+
+// FieldError describes a single invalid field in a ValidationError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// ValidationError collects field-level validation failures. The ErrorHandler
+// middleware renders it as a 422 Unprocessable Entity with the field list.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// NewValidationError creates an empty ValidationError to add field errors to.
+//
+// Example usage:
+//
+//	verr := util.NewValidationError()
+//	if req.Email == "" {
+//	    verr.Add("email", "is required")
+//	}
+//	if req.Age < 18 {
+//	    verr.AddCode("age", "min", "must be at least 18")
+//	}
+//	if err := verr.ErrOrNil(); err != nil {
+//	    return err
+//	}
+func NewValidationError() *ValidationError {
+	return &ValidationError{}
+}
+
+// Add records a field error without a code and returns e for chaining.
+func (e *ValidationError) Add(field, message string) *ValidationError {
+	return e.AddCode(field, "", message)
+}
+
+// AddCode records a field error with a machine-readable code (e.g. "required", "min")
+// and returns e for chaining.
+func (e *ValidationError) AddCode(field, code, message string) *ValidationError {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message, Code: code})
+	return e
+}
+
+// HasErrors reports whether any field errors were added.
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+// ErrOrNil returns e if it has field errors and nil otherwise, avoiding a non-nil
+// error interface holding an empty ValidationError.
+func (e *ValidationError) ErrOrNil() error {
+	if !e.HasErrors() {
+		return nil
+	}
+	return e
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return "validation failed"
+	}
+
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrorBuilder(t *testing.T) {
+	verr := NewValidationError().
+		Add("email", "is required").
+		AddCode("age", "min", "must be at least 18")
+
+	require.Len(t, verr.Fields, 2)
+	assert.Equal(t, FieldError{Field: "email", Message: "is required"}, verr.Fields[0])
+	assert.Equal(t, FieldError{Field: "age", Message: "must be at least 18", Code: "min"}, verr.Fields[1])
+	assert.Equal(t, "validation failed: email: is required; age: must be at least 18", verr.Error())
+}
+
+func TestValidationErrorErrOrNil(t *testing.T) {
+	verr := NewValidationError()
+	assert.NoError(t, verr.ErrOrNil())
+
+	verr.Add("name", "is required")
+	err := fmt.Errorf("create user: %w", verr.ErrOrNil())
+
+	var target *ValidationError
+	require.True(t, errors.As(err, &target))
+	assert.Same(t, verr, target)
+}